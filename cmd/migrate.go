@@ -13,6 +13,7 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gorm.io/gorm"
 )
 
 var migrateCmd = &cobra.Command{
@@ -65,6 +66,22 @@ func init() {
 }
 
 func createMigrator() (*migrations.Migrator, error) {
+	db, logger, err := connectDatabase()
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("getting sql.DB: %w", err)
+	}
+
+	return migrations.NewMigrator(sqlDB, logger, migrations.Config{})
+}
+
+// connectDatabase opens the database configured in the config file and
+// APP_DATABASE_* environment variables.
+func connectDatabase() (*gorm.DB, *slog.Logger, error) {
 	if err := godotenv.Load(); err == nil {
 		slog.Info("Loaded .env file")
 	}
@@ -75,7 +92,7 @@ func createMigrator() (*migrations.Migrator, error) {
 		v.SetConfigFile(cfgFile)
 		if err := v.ReadInConfig(); err != nil {
 			if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
-				return nil, fmt.Errorf("reading config file: %w", err)
+				return nil, nil, fmt.Errorf("reading config file: %w", err)
 			}
 		}
 	}
@@ -86,24 +103,18 @@ func createMigrator() (*migrations.Migrator, error) {
 
 	var dbConfig database.Config
 	if err := defaults.Set(&dbConfig); err != nil {
-		return nil, fmt.Errorf("setting defaults: %w", err)
+		return nil, nil, fmt.Errorf("setting defaults: %w", err)
 	}
 
 	if err := v.UnmarshalKey("database", &dbConfig); err != nil {
-		return nil, fmt.Errorf("parsing database config: %w", err)
+		return nil, nil, fmt.Errorf("parsing database config: %w", err)
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
 	db, err := database.NewConnection(logger, dbConfig)
 	if err != nil {
-		return nil, fmt.Errorf("connecting to database: %w", err)
-	}
-
-	sqlDB, err := db.DB()
-	if err != nil {
-		return nil, fmt.Errorf("getting sql.DB: %w", err)
+		return nil, nil, fmt.Errorf("connecting to database: %w", err)
 	}
-
-	return migrations.NewMigrator(sqlDB, logger, migrations.Config{}), nil
+	return db, logger, nil
 }
@@ -1,12 +1,21 @@
 package app
 
 import (
+	"log/slog"
+	"os"
+	"time"
+
 	"project_template/internal/secondboundedcontext"
 	"project_template/internal/someboundedcontext"
+	"project_template/pkg/auth"
 	"project_template/pkg/database"
+	"project_template/pkg/health"
+	"project_template/pkg/httpclient"
 	"project_template/pkg/logger"
+	"project_template/pkg/logger/loglevel"
 	"project_template/pkg/messagebus"
 	"project_template/pkg/migrations"
+	"project_template/pkg/shutdown"
 	"project_template/pkg/telemetry"
 	"project_template/pkg/validation"
 	"project_template/pkg/webserver"
@@ -14,14 +23,22 @@ import (
 	"go.uber.org/fx"
 )
 
+// stopTimeoutMargin leaves room for OnStop hooks outside the shutdown sequence.
+const stopTimeoutMargin = 5 * time.Second
+
 func generalModules() []fx.Option {
 	return []fx.Option{
 		logger.Module,
+		loglevel.Module,
+		shutdown.Module,
 		database.Module,
 		migrations.Module,
 		telemetry.Module,
+		httpclient.Module,
+		auth.Module,
 		messagebus.Module,
 		validation.Module,
+		health.Module,
 		someboundedcontext.Module,
 		secondboundedcontext.Module,
 	}
@@ -31,14 +48,26 @@ func middlewares() []fx.Option {
 	return []fx.Option{
 		fx.Provide(webserver.AsMiddleware(telemetry.NewHTTPMiddleware)),
 		fx.Provide(webserver.AsMiddleware(telemetry.NewHTTPMetricsMiddleware)),
+		fx.Provide(webserver.AsMiddleware(webserver.NewAuthFailureMiddleware)),
+		fx.Provide(webserver.AsMiddleware(auth.NewMiddleware)),
+		fx.Provide(webserver.AsMiddleware(auth.NewAPIKeyMiddleware)),
 	}
 }
 
 func Serve(configFile string) {
+	// Config is loaded up front because the fx stop timeout must cover
+	// the configured shutdown sequence.
+	cfg, err := NewServeConfig(configFile)()
+	if err != nil {
+		slog.Error("failed to load config", "error", err)
+		os.Exit(1)
+	}
+
 	fx.New(
 		fx.Options(generalModules()...),
 		fx.Options(middlewares()...),
-		fx.Provide(NewServeConfig(configFile)),
+		fx.Provide(func() Config { return cfg }),
+		fx.StopTimeout(cfg.Shutdown.TotalTimeout()+stopTimeoutMargin),
 		webserver.Module,
 	).Run()
 }
@@ -4,9 +4,14 @@ import (
 	"fmt"
 	"log/slog"
 	someboundedcontext "project_template/internal/someboundedcontext/config"
+	"project_template/pkg/auth"
 	"project_template/pkg/database"
+	"project_template/pkg/health"
+	"project_template/pkg/httpclient"
+	"project_template/pkg/logger"
 	"project_template/pkg/messagebus"
 	"project_template/pkg/migrations"
+	"project_template/pkg/shutdown"
 	"project_template/pkg/telemetry"
 	"project_template/pkg/webserver"
 	"strings"
@@ -26,6 +31,11 @@ type Config struct {
 	Migrations         migrations.Config         `mapstructure:"migrations"`
 	Telemetry          telemetry.Config          `mapstructure:"telemetry"`
 	MessageBus         messagebus.Config         `mapstructure:"messagebus"`
+	Health             health.Config             `mapstructure:"health"`
+	Shutdown           shutdown.Config           `mapstructure:"shutdown"`
+	Logger             logger.Config             `mapstructure:"logger"`
+	HTTPClient         httpclient.Config         `mapstructure:"httpclient"`
+	Auth               auth.Config               `mapstructure:"auth"`
 }
 
 func NewServeConfig(yamlConfigFile string) func() (Config, error) {
@@ -60,6 +70,45 @@ func NewServeConfig(yamlConfigFile string) func() (Config, error) {
 		_ = v.BindEnv("telemetry.service_name", "APP_TELEMETRY_SERVICE_NAME")
 		_ = v.BindEnv("telemetry.otlp_endpoint", "APP_TELEMETRY_OTLP_ENDPOINT")
 		_ = v.BindEnv("telemetry.insecure", "APP_TELEMETRY_INSECURE")
+		_ = v.BindEnv("telemetry.logs", "APP_TELEMETRY_LOGS")
+		_ = v.BindEnv("telemetry.service_version", "APP_TELEMETRY_SERVICE_VERSION")
+		_ = v.BindEnv("telemetry.environment", "APP_TELEMETRY_ENVIRONMENT")
+		_ = v.BindEnv("telemetry.exporter", "APP_TELEMETRY_EXPORTER")
+		_ = v.BindEnv("telemetry.protocol", "APP_TELEMETRY_PROTOCOL")
+		_ = v.BindEnv("telemetry.file_path", "APP_TELEMETRY_FILE_PATH")
+		_ = v.BindEnv("telemetry.sample_ratio", "APP_TELEMETRY_SAMPLE_RATIO")
+		_ = v.BindEnv("telemetry.metric_interval", "APP_TELEMETRY_METRIC_INTERVAL")
+		_ = v.BindEnv("telemetry.max_queue_size", "APP_TELEMETRY_MAX_QUEUE_SIZE")
+		_ = v.BindEnv("telemetry.export_timeout", "APP_TELEMETRY_EXPORT_TIMEOUT")
+		_ = v.BindEnv("telemetry.retry_max_elapsed", "APP_TELEMETRY_RETRY_MAX_ELAPSED")
+		_ = v.BindEnv("telemetry.prometheus.enabled", "APP_TELEMETRY_PROMETHEUS_ENABLED")
+		_ = v.BindEnv("telemetry.prometheus.address", "APP_TELEMETRY_PROMETHEUS_ADDRESS")
+
+		// Bind rate limit config keys explicitly
+		_ = v.BindEnv("webserver.ratelimit.enabled", "APP_WEBSERVER_RATELIMIT_ENABLED")
+		_ = v.BindEnv("webserver.ratelimit.store", "APP_WEBSERVER_RATELIMIT_STORE")
+		_ = v.BindEnv("webserver.ratelimit.rate", "APP_WEBSERVER_RATELIMIT_RATE")
+		_ = v.BindEnv("webserver.ratelimit.burst", "APP_WEBSERVER_RATELIMIT_BURST")
+		_ = v.BindEnv("webserver.ratelimit.trust_forwarded_for", "APP_WEBSERVER_RATELIMIT_TRUST_FORWARDED_FOR")
+		_ = v.BindEnv("webserver.ratelimit.auth_failure_rate", "APP_WEBSERVER_RATELIMIT_AUTH_FAILURE_RATE")
+		_ = v.BindEnv("webserver.ratelimit.auth_failure_burst", "APP_WEBSERVER_RATELIMIT_AUTH_FAILURE_BURST")
+
+		// Bind logger config keys explicitly
+		_ = v.BindEnv("logger.level", "APP_LOGGER_LEVEL")
+		_ = v.BindEnv("logger.format", "APP_LOGGER_FORMAT")
+		_ = v.BindEnv("logger.output", "APP_LOGGER_OUTPUT")
+		_ = v.BindEnv("logger.debug_timeout", "APP_LOGGER_DEBUG_TIMEOUT")
+		_ = v.BindEnv("logger.admin_token", "APP_LOGGER_ADMIN_TOKEN")
+
+		// Bind auth config keys explicitly
+		_ = v.BindEnv("auth.enabled", "APP_AUTH_ENABLED")
+		_ = v.BindEnv("auth.jwks_file", "APP_AUTH_JWKS_FILE")
+		_ = v.BindEnv("auth.jwks_url", "APP_AUTH_JWKS_URL")
+		_ = v.BindEnv("auth.issuer", "APP_AUTH_ISSUER")
+		_ = v.BindEnv("auth.audience", "APP_AUTH_AUDIENCE")
+		_ = v.BindEnv("auth.require_by_default", "APP_AUTH_REQUIRE_BY_DEFAULT")
+		_ = v.BindEnv("auth.api_keys.enabled", "APP_AUTH_API_KEYS_ENABLED")
+		_ = v.BindEnv("auth.api_keys.cache_ttl", "APP_AUTH_API_KEYS_CACHE_TTL")
 
 		// Unmarshal to struct
 		var cfg Config
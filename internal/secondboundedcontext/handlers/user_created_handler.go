@@ -28,11 +28,11 @@ func (h *UserCreatedHandler) Topic() string {
 func (h *UserCreatedHandler) Handle(ctx context.Context, payload []byte) error {
 	var event events.UserCreatedEvent
 	if err := json.Unmarshal(payload, &event); err != nil {
-		h.logger.Error("failed to unmarshal UserCreatedEvent", "error", err)
+		h.logger.ErrorContext(ctx, "failed to unmarshal UserCreatedEvent", "error", err)
 		return err
 	}
 
-	h.logger.Info("received UserCreatedEvent",
+	h.logger.InfoContext(ctx, "received UserCreatedEvent",
 		"user_id", event.UserID,
 		"name", event.Name,
 		"email", event.Email,
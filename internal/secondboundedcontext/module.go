@@ -2,12 +2,14 @@ package secondboundedcontext
 
 import (
 	"project_template/internal/secondboundedcontext/handlers"
+	"project_template/pkg/logger"
 	"project_template/pkg/messagebus"
 
 	"go.uber.org/fx"
 )
 
 var Module = fx.Module("secondboundedcontext",
+	logger.ForModule("secondboundedcontext"),
 	fx.Provide(
 		messagebus.AsHandler(handlers.NewUserCreatedHandler),
 	),
@@ -6,61 +6,121 @@ import (
 )
 
 type AppError struct {
-	Code    int    `json:"-"`
-	Message string `json:"message"`
-	Details any    `json:"details,omitempty"`
+	Code int `json:"-"`
+	// ErrorCode is a stable, machine-readable identifier of the error.
+	ErrorCode string `json:"code,omitempty"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+
+	// Problem details members (RFC 7807), used when the client accepts
+	// application/problem+json. Empty Type and Title fall back to
+	// "about:blank" and the status text, empty Instance to the request path.
+	Type       string         `json:"-"`
+	Title      string         `json:"-"`
+	Instance   string         `json:"-"`
+	Extensions map[string]any `json:"-"`
 }
 
 func (e *AppError) Error() string {
 	return e.Message
 }
 
+// WithType sets the problem type URI identifying the kind of error.
+func (e *AppError) WithType(uri string) *AppError {
+	e.Type = uri
+	return e
+}
+
+// WithTitle sets a short, human-readable summary of the problem type.
+func (e *AppError) WithTitle(title string) *AppError {
+	e.Title = title
+	return e
+}
+
+// WithInstance sets the URI identifying this occurrence of the problem.
+func (e *AppError) WithInstance(uri string) *AppError {
+	e.Instance = uri
+	return e
+}
+
+// WithExtension adds an extension member to the problem details.
+func (e *AppError) WithExtension(key string, value any) *AppError {
+	if e.Extensions == nil {
+		e.Extensions = make(map[string]any)
+	}
+	e.Extensions[key] = value
+	return e
+}
+
 func NewBadRequest(message string) *AppError {
 	return &AppError{
-		Code:    http.StatusBadRequest,
-		Message: message,
+		Code:      http.StatusBadRequest,
+		ErrorCode: "bad_request",
+		Message:   message,
 	}
 }
 
 func NewNotFound(resource string) *AppError {
 	return &AppError{
-		Code:    http.StatusNotFound,
-		Message: fmt.Sprintf("%s not found", resource),
+		Code:      http.StatusNotFound,
+		ErrorCode: "not_found",
+		Message:   fmt.Sprintf("%s not found", resource),
 	}
 }
 
 func NewUnprocessableEntity(message string, details any) *AppError {
 	return &AppError{
-		Code:    http.StatusUnprocessableEntity,
-		Message: message,
-		Details: details,
+		Code:      http.StatusUnprocessableEntity,
+		ErrorCode: "validation_failed",
+		Message:   message,
+		Details:   details,
 	}
 }
 
 func NewInternalError(message string) *AppError {
 	return &AppError{
-		Code:    http.StatusInternalServerError,
-		Message: message,
+		Code:      http.StatusInternalServerError,
+		ErrorCode: "internal",
+		Message:   message,
 	}
 }
 
 func NewConflict(message string) *AppError {
 	return &AppError{
-		Code:    http.StatusConflict,
-		Message: message,
+		Code:      http.StatusConflict,
+		ErrorCode: "conflict",
+		Message:   message,
 	}
 }
 
 func NewUnauthorized(message string) *AppError {
 	return &AppError{
-		Code:    http.StatusUnauthorized,
-		Message: message,
+		Code:      http.StatusUnauthorized,
+		ErrorCode: "unauthorized",
+		Message:   message,
 	}
 }
 
 func NewForbidden(message string) *AppError {
 	return &AppError{
-		Code:    http.StatusForbidden,
-		Message: message,
+		Code:      http.StatusForbidden,
+		ErrorCode: "forbidden",
+		Message:   message,
+	}
+}
+
+func NewUnsupportedMediaType(message string) *AppError {
+	return &AppError{
+		Code:      http.StatusUnsupportedMediaType,
+		ErrorCode: "unsupported_media_type",
+		Message:   message,
+	}
+}
+
+func NewTooManyRequests(message string) *AppError {
+	return &AppError{
+		Code:      http.StatusTooManyRequests,
+		ErrorCode: "rate_limited",
+		Message:   message,
 	}
 }
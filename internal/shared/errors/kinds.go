@@ -0,0 +1,164 @@
+package errors
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Kind classifies a domain error independently of the transport.
+// Services return errors of a kind; the HTTP layer maps kinds to status
+// codes in one place.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindInvalid
+	KindNotFound
+	KindConflict
+	KindUnauthorized
+	KindForbidden
+	KindUnavailable
+	KindRateLimited
+)
+
+func (k Kind) String() string {
+	switch k {
+	case KindInvalid:
+		return "invalid"
+	case KindNotFound:
+		return "not_found"
+	case KindConflict:
+		return "conflict"
+	case KindUnauthorized:
+		return "unauthorized"
+	case KindForbidden:
+		return "forbidden"
+	case KindUnavailable:
+		return "unavailable"
+	case KindRateLimited:
+		return "rate_limited"
+	default:
+		return "internal"
+	}
+}
+
+// HTTPStatus returns the status code responses for errors of kind k use.
+func (k Kind) HTTPStatus() int {
+	switch k {
+	case KindInvalid:
+		return http.StatusBadRequest
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindConflict:
+		return http.StatusConflict
+	case KindUnauthorized:
+		return http.StatusUnauthorized
+	case KindForbidden:
+		return http.StatusForbidden
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	case KindRateLimited:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Error is a classified domain error. Code is a stable, machine-readable
+// identifier such as "user_not_found" that clients can rely on; Message is
+// meant for humans and may change.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Err     error
+}
+
+// New creates a domain error. It is typically assigned to a package-level
+// sentinel that callers test with errors.Is.
+func New(kind Kind, code, message string) *Error {
+	return &Error{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+	}
+}
+
+// Wrap returns a copy of e that wraps cause. The copy still matches e with
+// errors.Is.
+func (e *Error) Wrap(cause error) *Error {
+	wrapped := *e
+	wrapped.Err = cause
+	return &wrapped
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is a domain error with the same kind and code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Kind == e.Kind && t.Code == e.Code
+}
+
+// KindOf returns the kind of the first classified error in err's chain,
+// or KindInternal if there is none.
+func KindOf(err error) Kind {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Kind
+	}
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return kindFromStatus(appErr.Code)
+	}
+	return KindInternal
+}
+
+// ToAppError finds the AppError or classified domain error in err's chain
+// and returns its HTTP representation. It returns nil for unclassified
+// errors and for KindInternal, whose messages must not reach clients.
+func ToAppError(err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	var domainErr *Error
+	if errors.As(err, &domainErr) && domainErr.Kind != KindInternal {
+		return &AppError{
+			Code:      domainErr.Kind.HTTPStatus(),
+			ErrorCode: domainErr.Code,
+			Message:   domainErr.Message,
+		}
+	}
+	return nil
+}
+
+func kindFromStatus(status int) Kind {
+	switch status {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusUnsupportedMediaType:
+		return KindInvalid
+	case http.StatusNotFound:
+		return KindNotFound
+	case http.StatusConflict:
+		return KindConflict
+	case http.StatusUnauthorized:
+		return KindUnauthorized
+	case http.StatusForbidden:
+		return KindForbidden
+	case http.StatusServiceUnavailable:
+		return KindUnavailable
+	case http.StatusTooManyRequests:
+		return KindRateLimited
+	default:
+		return KindInternal
+	}
+}
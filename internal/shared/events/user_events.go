@@ -2,7 +2,12 @@ package events
 
 import "github.com/google/uuid"
 
-const TopicUserCreated = "user.created"
+const (
+	TopicUserCreated = "user.created"
+	TopicUserUpdated = "user.updated"
+	TopicUserDeleted = "user.deleted"
+	TopicUserErased  = "user.erased"
+)
 
 // UserCreatedEvent is published when a new user is created.
 type UserCreatedEvent struct {
@@ -14,3 +19,33 @@ type UserCreatedEvent struct {
 func (e UserCreatedEvent) Topic() string {
 	return TopicUserCreated
 }
+
+// UserUpdatedEvent is published when a user is replaced or patched.
+type UserUpdatedEvent struct {
+	UserID uuid.UUID `json:"user_id"`
+	Name   string    `json:"name"`
+	Email  string    `json:"email"`
+}
+
+func (e UserUpdatedEvent) Topic() string {
+	return TopicUserUpdated
+}
+
+// UserDeletedEvent is published when a user is soft-deleted.
+type UserDeletedEvent struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (e UserDeletedEvent) Topic() string {
+	return TopicUserDeleted
+}
+
+// UserErasedEvent is published when a user is permanently erased.
+// Consumers holding personal data about the user must delete it.
+type UserErasedEvent struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+func (e UserErasedEvent) Topic() string {
+	return TopicUserErased
+}
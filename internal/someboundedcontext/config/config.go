@@ -1,5 +1,17 @@
 package config
 
+import "time"
+
 type Config struct {
 	UIDPrefix string `default:"some_uid_"`
+
+	// PurgeEnabled turns on the background job that erases soft-deleted
+	// users. Erasing cannot be undone, so the job is off unless enabled.
+	PurgeEnabled bool `mapstructure:"purge_enabled" default:"false"`
+	// PurgeRetention is how long soft-deleted users are kept before being erased.
+	PurgeRetention time.Duration `mapstructure:"purge_retention" default:"720h"`
+	// PurgeInterval is how often the purge job runs.
+	PurgeInterval time.Duration `mapstructure:"purge_interval" default:"1h"`
+	// PurgeBatchSize caps the number of users erased per batch.
+	PurgeBatchSize int `mapstructure:"purge_batch_size" default:"100"`
 }
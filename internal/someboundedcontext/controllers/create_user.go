@@ -2,24 +2,23 @@ package controller
 
 import (
 	"encoding/json/v2"
-	"log/slog"
+	"fmt"
 	"net/http"
 
 	apperrors "project_template/internal/shared/errors"
 	"project_template/internal/someboundedcontext/dto"
 	"project_template/internal/someboundedcontext/services"
 	"project_template/pkg/validation"
+	"project_template/pkg/webserver"
 )
 
 type CreateUserHandler struct {
-	logger      *slog.Logger
 	userService *services.UserService
 	validator   *validation.Validator
 }
 
-func NewCreateUserHandler(logger *slog.Logger, userService *services.UserService, validator *validation.Validator) *CreateUserHandler {
+func NewCreateUserHandler(userService *services.UserService, validator *validation.Validator) *CreateUserHandler {
 	return &CreateUserHandler{
-		logger:      logger,
 		userService: userService,
 		validator:   validator,
 	}
@@ -29,6 +28,16 @@ func (*CreateUserHandler) Pattern() string {
 	return "POST /api/users"
 }
 
+func (*CreateUserHandler) RequiredPermissions() []string {
+	return []string{services.PermissionWriteUsers}
+}
+
+// RateLimit keeps a client from creating users in bulk: five at once, then
+// one every five seconds.
+func (*CreateUserHandler) RateLimit() webserver.RateLimit {
+	return webserver.RateLimit{Rate: 0.2, Burst: 5}
+}
+
 func (h *CreateUserHandler) Handle(w http.ResponseWriter, r *http.Request) error {
 	var user dto.CreateUserRequest
 	if err := json.UnmarshalRead(r.Body, &user); err != nil {
@@ -41,8 +50,7 @@ func (h *CreateUserHandler) Handle(w http.ResponseWriter, r *http.Request) error
 
 	createdUser, err := h.userService.CreateUser(r.Context(), user)
 	if err != nil {
-		h.logger.Error("failed to create user", "error", err)
-		return apperrors.NewInternalError("failed to create user")
+		return fmt.Errorf("creating user: %w", err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
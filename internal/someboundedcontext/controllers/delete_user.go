@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/internal/someboundedcontext/services"
+)
+
+// DeleteUserHandler handles DELETE /api/users/{id}
+type DeleteUserHandler struct {
+	userService *services.UserService
+}
+
+func NewDeleteUserHandler(userService *services.UserService) *DeleteUserHandler {
+	return &DeleteUserHandler{
+		userService: userService,
+	}
+}
+
+func (*DeleteUserHandler) Pattern() string {
+	return "DELETE /api/users/{id}"
+}
+
+func (*DeleteUserHandler) RequiresAuth() bool {
+	return true
+}
+
+func (h *DeleteUserHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return apperrors.NewBadRequest("user id required")
+	}
+
+	if err := h.userService.DeleteUser(r.Context(), id); err != nil {
+		return fmt.Errorf("deleting user: %w", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
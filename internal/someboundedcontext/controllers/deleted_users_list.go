@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"net/http"
+
+	"project_template/internal/someboundedcontext/services"
+	"project_template/pkg/validation"
+)
+
+// DeletedUsersHandler handles GET /api/admin/users/deleted
+//
+// It accepts the same query parameters as UsersHandler and lists
+// soft-deleted users only.
+type DeletedUsersHandler struct {
+	userService *services.UserService
+	validator   *validation.Validator
+}
+
+func NewDeletedUsersHandler(userService *services.UserService, validator *validation.Validator) *DeletedUsersHandler {
+	return &DeletedUsersHandler{
+		userService: userService,
+		validator:   validator,
+	}
+}
+
+func (*DeletedUsersHandler) Pattern() string {
+	return "GET /api/admin/users/deleted"
+}
+
+func (*DeletedUsersHandler) RequiredPermissions() []string {
+	return []string{services.PermissionAdminUsers}
+}
+
+func (h *DeletedUsersHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	req, err := parseListUsersRequest(r.URL.Query())
+	if err != nil {
+		return err
+	}
+	req.Trashed = true
+
+	if err := h.validator.Validate(req); err != nil {
+		return err
+	}
+
+	users, err := h.userService.ListUsers(r.Context(), req)
+	if err != nil {
+		return fmt.Errorf("listing deleted users: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.MarshalWrite(w, users)
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"fmt"
+	"net/http"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/internal/someboundedcontext/services"
+)
+
+// EraseUserHandler handles DELETE /api/admin/users/{id}
+//
+// Unlike DELETE /api/users/{id}, the user is removed permanently.
+type EraseUserHandler struct {
+	userService *services.UserService
+}
+
+func NewEraseUserHandler(userService *services.UserService) *EraseUserHandler {
+	return &EraseUserHandler{
+		userService: userService,
+	}
+}
+
+func (*EraseUserHandler) Pattern() string {
+	return "DELETE /api/admin/users/{id}"
+}
+
+func (*EraseUserHandler) RequiredPermissions() []string {
+	return []string{services.PermissionAdminUsers}
+}
+
+func (h *EraseUserHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return apperrors.NewBadRequest("user id required")
+	}
+
+	if err := h.userService.EraseUser(r.Context(), id); err != nil {
+		return fmt.Errorf("erasing user: %w", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
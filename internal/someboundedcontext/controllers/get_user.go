@@ -2,8 +2,7 @@ package controller
 
 import (
 	"encoding/json/v2"
-	"errors"
-	"log/slog"
+	"fmt"
 	"net/http"
 
 	apperrors "project_template/internal/shared/errors"
@@ -12,13 +11,11 @@ import (
 
 // UserHandler handles GET /api/users/{id}
 type UserHandler struct {
-	logger      *slog.Logger
 	userService *services.UserService
 }
 
-func NewUserHandler(logger *slog.Logger, userService *services.UserService) *UserHandler {
+func NewUserHandler(userService *services.UserService) *UserHandler {
 	return &UserHandler{
-		logger:      logger,
 		userService: userService,
 	}
 }
@@ -27,6 +24,10 @@ func (*UserHandler) Pattern() string {
 	return "GET /api/users/{id}"
 }
 
+func (*UserHandler) RequiresAuth() bool {
+	return true
+}
+
 func (h *UserHandler) Handle(w http.ResponseWriter, r *http.Request) error {
 	id := r.PathValue("id")
 	if id == "" {
@@ -35,11 +36,7 @@ func (h *UserHandler) Handle(w http.ResponseWriter, r *http.Request) error {
 
 	user, err := h.userService.GetUser(r.Context(), id)
 	if err != nil {
-		if errors.Is(err, services.ErrUserNotFound) {
-			return apperrors.NewNotFound("user")
-		}
-		h.logger.Error("failed to get user", "error", err)
-		return apperrors.NewInternalError("failed to get user")
+		return fmt.Errorf("getting user: %w", err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
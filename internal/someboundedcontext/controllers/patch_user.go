@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/internal/someboundedcontext/dto"
+	"project_template/internal/someboundedcontext/services"
+	"project_template/pkg/mergepatch"
+	"project_template/pkg/validation"
+)
+
+// PatchUserHandler handles PATCH /api/users/{id} with a JSON Merge Patch body.
+type PatchUserHandler struct {
+	userService *services.UserService
+	validator   *validation.Validator
+}
+
+func NewPatchUserHandler(userService *services.UserService, validator *validation.Validator) *PatchUserHandler {
+	return &PatchUserHandler{
+		userService: userService,
+		validator:   validator,
+	}
+}
+
+func (*PatchUserHandler) Pattern() string {
+	return "PATCH /api/users/{id}"
+}
+
+func (*PatchUserHandler) RequiresAuth() bool {
+	return true
+}
+
+func (h *PatchUserHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return apperrors.NewBadRequest("user id required")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != mergepatch.ContentType && mediaType != "application/json" {
+		return apperrors.NewUnsupportedMediaType("content type must be " + mergepatch.ContentType)
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		return apperrors.NewBadRequest("invalid request body")
+	}
+
+	updatedUser, err := h.userService.PatchUser(r.Context(), id, func(current dto.UpdateUserRequest) (dto.UpdateUserRequest, error) {
+		return h.apply(current, patch)
+	})
+	if err != nil {
+		return fmt.Errorf("patching user: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.MarshalWrite(w, updatedUser)
+}
+
+// apply merges patch into current and validates the result.
+func (h *PatchUserHandler) apply(current dto.UpdateUserRequest, patch []byte) (dto.UpdateUserRequest, error) {
+	doc, err := json.Marshal(current)
+	if err != nil {
+		return dto.UpdateUserRequest{}, err
+	}
+
+	patched, err := mergepatch.Apply(doc, patch)
+	if err != nil {
+		return dto.UpdateUserRequest{}, apperrors.NewBadRequest("invalid merge patch")
+	}
+
+	var user dto.UpdateUserRequest
+	if err := json.Unmarshal(patched, &user); err != nil {
+		return dto.UpdateUserRequest{}, apperrors.NewBadRequest("invalid merge patch")
+	}
+
+	if err := h.validator.Validate(user); err != nil {
+		return dto.UpdateUserRequest{}, err
+	}
+	return user, nil
+}
@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"net/http"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/internal/someboundedcontext/services"
+)
+
+// RestoreUserHandler handles POST /api/admin/users/{id}/restore
+type RestoreUserHandler struct {
+	userService *services.UserService
+}
+
+func NewRestoreUserHandler(userService *services.UserService) *RestoreUserHandler {
+	return &RestoreUserHandler{
+		userService: userService,
+	}
+}
+
+func (*RestoreUserHandler) Pattern() string {
+	return "POST /api/admin/users/{id}/restore"
+}
+
+func (*RestoreUserHandler) RequiredPermissions() []string {
+	return []string{services.PermissionAdminUsers}
+}
+
+func (h *RestoreUserHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return apperrors.NewBadRequest("user id required")
+	}
+
+	user, err := h.userService.RestoreUser(r.Context(), id)
+	if err != nil {
+		return fmt.Errorf("restoring user: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.MarshalWrite(w, user)
+}
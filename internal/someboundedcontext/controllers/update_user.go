@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"encoding/json/v2"
+	"fmt"
+	"net/http"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/internal/someboundedcontext/dto"
+	"project_template/internal/someboundedcontext/services"
+	"project_template/pkg/validation"
+)
+
+// UpdateUserHandler handles PUT /api/users/{id}
+type UpdateUserHandler struct {
+	userService *services.UserService
+	validator   *validation.Validator
+}
+
+func NewUpdateUserHandler(userService *services.UserService, validator *validation.Validator) *UpdateUserHandler {
+	return &UpdateUserHandler{
+		userService: userService,
+		validator:   validator,
+	}
+}
+
+func (*UpdateUserHandler) Pattern() string {
+	return "PUT /api/users/{id}"
+}
+
+func (*UpdateUserHandler) RequiresAuth() bool {
+	return true
+}
+
+func (h *UpdateUserHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if id == "" {
+		return apperrors.NewBadRequest("user id required")
+	}
+
+	var user dto.UpdateUserRequest
+	if err := json.UnmarshalRead(r.Body, &user); err != nil {
+		return apperrors.NewBadRequest("invalid request body")
+	}
+
+	if err := h.validator.Validate(user); err != nil {
+		return err
+	}
+
+	updatedUser, err := h.userService.UpdateUser(r.Context(), id, user)
+	if err != nil {
+		return fmt.Errorf("updating user: %w", err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.MarshalWrite(w, updatedUser)
+}
@@ -2,22 +2,41 @@ package controller
 
 import (
 	"encoding/json/v2"
-	"log/slog"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 
 	apperrors "project_template/internal/shared/errors"
+	"project_template/internal/someboundedcontext/dto"
 	"project_template/internal/someboundedcontext/services"
+	"project_template/pkg/validation"
 )
 
+const defaultUsersPageLimit = 20
+
+// UsersHandler handles GET /api/users
+//
+// Query parameters:
+//   - limit: page size, 1..100 (default 20)
+//   - cursor: opaque cursor from next_cursor or prev_cursor of a previous page,
+//     valid only with the sort and filters of that page
+//   - offset: number of users to skip, for offset pagination instead of cursors
+//   - page: 1-based page number, an alternative to offset
+//   - sort: created_at, name or email, prefixed with "-" for descending (default -created_at)
+//   - name, email: case-insensitive substring filters
+//   - created_after, created_before: RFC 3339 timestamps bounding created_at
+//   - include_total: if true, the response includes the total number of matches
 type UsersHandler struct {
-	logger      *slog.Logger
 	userService *services.UserService
+	validator   *validation.Validator
 }
 
-func NewUsersHandler(logger *slog.Logger, userService *services.UserService) *UsersHandler {
+func NewUsersHandler(userService *services.UserService, validator *validation.Validator) *UsersHandler {
 	return &UsersHandler{
-		logger:      logger,
 		userService: userService,
+		validator:   validator,
 	}
 }
 
@@ -25,13 +44,95 @@ func (*UsersHandler) Pattern() string {
 	return "GET /api/users"
 }
 
+func (*UsersHandler) RequiredPermissions() []string {
+	return []string{services.PermissionReadUsers}
+}
+
 func (h *UsersHandler) Handle(w http.ResponseWriter, r *http.Request) error {
-	users, err := h.userService.ListUsers(r.Context())
+	req, err := parseListUsersRequest(r.URL.Query())
+	if err != nil {
+		return err
+	}
+
+	if err := h.validator.Validate(req); err != nil {
+		return err
+	}
+
+	users, err := h.userService.ListUsers(r.Context(), req)
 	if err != nil {
-		h.logger.Error("failed to list users", "error", err)
-		return apperrors.NewInternalError("failed to list users")
+		return fmt.Errorf("listing users: %w", err)
 	}
 
 	w.Header().Set("Content-Type", "application/json")
 	return json.MarshalWrite(w, users)
 }
+
+func parseListUsersRequest(q url.Values) (dto.ListUsersRequest, error) {
+	req := dto.ListUsersRequest{
+		Limit:  defaultUsersPageLimit,
+		Cursor: q.Get("cursor"),
+		Sort:   q.Get("sort"),
+		Name:   q.Get("name"),
+		Email:  q.Get("email"),
+	}
+
+	if v := q.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil {
+			return req, apperrors.NewBadRequest("limit must be an integer")
+		}
+		req.Limit = limit
+	}
+
+	if q.Has("offset") && q.Has("page") {
+		return req, apperrors.NewBadRequest("offset and page cannot be combined")
+	}
+	if v := q.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil {
+			return req, apperrors.NewBadRequest("offset must be an integer")
+		}
+		req.Offset = &offset
+	}
+	if v := q.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return req, apperrors.NewBadRequest("page must be a positive integer")
+		}
+		offset := (page - 1) * req.Limit
+		req.Offset = &offset
+	}
+	if req.Offset != nil && req.Cursor != "" {
+		return req, apperrors.NewBadRequest("cursor cannot be combined with offset or page")
+	}
+
+	if v := q.Get("include_total"); v != "" {
+		includeTotal, err := strconv.ParseBool(v)
+		if err != nil {
+			return req, apperrors.NewBadRequest("include_total must be a boolean")
+		}
+		req.IncludeTotal = includeTotal
+	}
+
+	var err error
+	if req.CreatedAfter, err = parseTimeParam(q, "created_after"); err != nil {
+		return req, err
+	}
+	if req.CreatedBefore, err = parseTimeParam(q, "created_before"); err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
+
+func parseTimeParam(q url.Values, name string) (*time.Time, error) {
+	v := q.Get(name)
+	if v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil, apperrors.NewBadRequest(name + " must be an RFC 3339 timestamp")
+	}
+	return &t, nil
+}
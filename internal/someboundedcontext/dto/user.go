@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 )
 
@@ -9,10 +11,46 @@ type CreateUserRequest struct {
 	Email string `json:"email" validate:"required,email"`
 }
 
+// UpdateUserRequest replaces the mutable fields of a user. It is also the
+// document a JSON Merge Patch is applied to.
+type UpdateUserRequest struct {
+	Name  string `json:"name" validate:"required,min=2,max=100"`
+	Email string `json:"email" validate:"required,email"`
+}
+
 type UserResponse struct {
-	ID    uuid.UUID `json:"id"`
-	Name  string    `json:"name"`
-	Email string    `json:"email"`
+	ID        uuid.UUID  `json:"id"`
+	Name      string     `json:"name"`
+	Email     string     `json:"email"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
 }
 
 type UsersListResponse []UserResponse
+
+// ListUsersRequest holds the query parameters of GET /api/users.
+type ListUsersRequest struct {
+	Limit  int    `validate:"min=1,max=100"`
+	Cursor string `validate:"omitempty,max=512"`
+	// Offset selects offset pagination instead of cursors when set.
+	Offset        *int   `validate:"omitempty,min=0,max=10000"`
+	Sort          string `validate:"omitempty,oneof=created_at -created_at name -name email -email"`
+	Name          string `validate:"omitempty,max=100"`
+	Email         string `validate:"omitempty,max=255"`
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	IncludeTotal  bool
+	// Trashed lists soft-deleted users. It is set by the admin endpoint,
+	// not parsed from the query string.
+	Trashed bool
+}
+
+// UsersPageResponse is a page of users with cursors to the neighbouring
+// pages, or their offsets if the page was requested by offset.
+type UsersPageResponse struct {
+	Data       UsersListResponse `json:"data"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+	PrevCursor string            `json:"prev_cursor,omitempty"`
+	NextOffset *int              `json:"next_offset,omitempty"`
+	PrevOffset *int              `json:"prev_offset,omitempty"`
+	Total      *int64            `json:"total,omitempty"`
+}
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"project_template/internal/someboundedcontext/config"
+	"project_template/internal/someboundedcontext/services"
+	"project_template/pkg/shutdown"
+
+	"go.uber.org/fx"
+)
+
+// PurgeDeletedUsersJob periodically erases users that have been
+// soft-deleted for longer than the configured retention.
+type PurgeDeletedUsersJob struct {
+	logger      *slog.Logger
+	config      config.Config
+	userService *services.UserService
+}
+
+func NewPurgeDeletedUsersJob(logger *slog.Logger, config config.Config, userService *services.UserService) (*PurgeDeletedUsersJob, error) {
+	if config.PurgeEnabled {
+		if config.PurgeRetention <= 0 {
+			return nil, fmt.Errorf("purge_retention must be positive, got %s", config.PurgeRetention)
+		}
+		if config.PurgeInterval <= 0 {
+			return nil, fmt.Errorf("purge_interval must be positive, got %s", config.PurgeInterval)
+		}
+		if config.PurgeBatchSize <= 0 {
+			return nil, fmt.Errorf("purge_batch_size must be positive, got %d", config.PurgeBatchSize)
+		}
+	}
+	return &PurgeDeletedUsersJob{
+		logger:      logger,
+		config:      config,
+		userService: userService,
+	}, nil
+}
+
+// Run purges in batches until no expired users remain.
+func (j *PurgeDeletedUsersJob) Run(ctx context.Context) {
+	cutoff := time.Now().Add(-j.config.PurgeRetention)
+	total := 0
+	for {
+		erased, err := j.userService.PurgeDeletedUsers(ctx, cutoff, j.config.PurgeBatchSize)
+		total += erased
+		if err != nil {
+			j.logger.ErrorContext(ctx, "failed to purge deleted users", "error", err, "erased", total)
+			return
+		}
+		if erased < j.config.PurgeBatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+	if total > 0 {
+		j.logger.InfoContext(ctx, "purged deleted users", "erased", total, "cutoff", cutoff)
+	}
+}
+
+// StartPurgeDeletedUsersJob runs the job on a ticker started by the fx
+// lifecycle and stopped by the shutdown coordinator.
+func StartPurgeDeletedUsersJob(lc fx.Lifecycle, job *PurgeDeletedUsersJob, coordinator *shutdown.Coordinator) {
+	if !job.config.PurgeEnabled {
+		job.logger.Info("deleted users purge job disabled")
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go func() {
+				defer close(done)
+				ticker := time.NewTicker(job.config.PurgeInterval)
+				defer ticker.Stop()
+				for {
+					job.Run(ctx)
+					select {
+					case <-ctx.Done():
+						return
+					case <-ticker.C:
+					}
+				}
+			}()
+			return nil
+		},
+	})
+	coordinator.Register(shutdown.PhaseBackground, "purge deleted users", func(stopCtx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-stopCtx.Done():
+			return stopCtx.Err()
+		}
+	})
+}
@@ -2,19 +2,31 @@ package someboundedcontext
 
 import (
 	controller "project_template/internal/someboundedcontext/controllers"
+	"project_template/internal/someboundedcontext/jobs"
 	"project_template/internal/someboundedcontext/repositories"
 	"project_template/internal/someboundedcontext/services"
+	"project_template/pkg/logger"
 	"project_template/pkg/webserver"
 
 	"go.uber.org/fx"
 )
 
 var Module = fx.Module("someboundedcontext",
+	logger.ForModule("someboundedcontext"),
 	fx.Provide(
 		services.NewUserService,
+		services.NewUserMetrics,
 		repositories.NewUserRepository,
+		jobs.NewPurgeDeletedUsersJob,
 		webserver.AsAppRoute(controller.NewUserHandler),
 		webserver.AsAppRoute(controller.NewUsersHandler),
 		webserver.AsAppRoute(controller.NewCreateUserHandler),
+		webserver.AsAppRoute(controller.NewUpdateUserHandler),
+		webserver.AsAppRoute(controller.NewPatchUserHandler),
+		webserver.AsAppRoute(controller.NewDeleteUserHandler),
+		webserver.AsAppRoute(controller.NewDeletedUsersHandler),
+		webserver.AsAppRoute(controller.NewRestoreUserHandler),
+		webserver.AsAppRoute(controller.NewEraseUserHandler),
 	),
+	fx.Invoke(jobs.StartPurgeDeletedUsersJob),
 )
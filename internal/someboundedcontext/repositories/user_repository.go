@@ -2,87 +2,195 @@ package repositories
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json/v2"
 	"errors"
+	"strings"
+	"time"
 
 	"project_template/internal/someboundedcontext/entities"
-	"project_template/pkg/telemetry"
+	"project_template/pkg/database"
 
 	"github.com/google/uuid"
-	"go.opentelemetry.io/otel/attribute"
 	"gorm.io/gorm"
 )
 
 type UserRepository struct {
-	db *gorm.DB
+	*database.Repository[entities.User, uuid.UUID]
 }
 
 func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{
-		db: db,
+		Repository: database.NewRepository[entities.User, uuid.UUID](db, "UserRepository"),
 	}
 }
 
 func (r *UserRepository) Create(ctx context.Context, user *entities.User) error {
-	ctx, span := telemetry.StartRepositorySpan(ctx, "UserRepository", "Create")
-	defer span.End()
-
-	err := r.db.WithContext(ctx).Create(user).Error
-	if err != nil {
-		telemetry.RecordError(span, err)
+	err := r.Repository.Create(ctx, user)
+	if errors.Is(err, database.ErrUniqueViolation) {
+		return ErrUserAlreadyExists
 	}
 	return err
 }
 
 func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entities.User, error) {
-	ctx, span := telemetry.StartRepositorySpan(ctx, "UserRepository", "GetByID")
-	defer span.End()
-	span.SetAttributes(attribute.String("user.id", id.String()))
-
-	var user entities.User
-	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&user).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, ErrUserNotFound
-		}
-		telemetry.RecordError(span, err)
-		return nil, err
+	user, err := r.Repository.GetByID(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrUserNotFound
 	}
-	return &user, nil
+	return user, err
 }
 
-func (r *UserRepository) GetAll(ctx context.Context) ([]*entities.User, error) {
-	ctx, span := telemetry.StartRepositorySpan(ctx, "UserRepository", "GetAll")
-	defer span.End()
-
-	var users []*entities.User
-	err := r.db.WithContext(ctx).Find(&users).Error
-	if err != nil {
-		telemetry.RecordError(span, err)
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*entities.User, error) {
+	user, err := r.FindOne(ctx, database.Query[entities.User]{
+		Where: []database.Specification[entities.User]{database.Eq[entities.User]("email", email)},
+	})
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrUserNotFound
 	}
-	span.SetAttributes(attribute.Int("users.count", len(users)))
-	return users, err
+	return user, err
 }
 
-func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
-	ctx, span := telemetry.StartRepositorySpan(ctx, "UserRepository", "Update")
-	defer span.End()
-	span.SetAttributes(attribute.String("user.id", user.ID.String()))
+func (r *UserRepository) GetAll(ctx context.Context) ([]*entities.User, error) {
+	return r.Find(ctx, database.Query[entities.User]{})
+}
 
-	err := r.db.WithContext(ctx).Save(user).Error
-	if err != nil {
-		telemetry.RecordError(span, err)
+func (r *UserRepository) Update(ctx context.Context, user *entities.User) error {
+	err := r.Repository.Update(ctx, user)
+	if errors.Is(err, database.ErrUniqueViolation) {
+		return ErrUserAlreadyExists
 	}
 	return err
 }
 
 func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	ctx, span := telemetry.StartRepositorySpan(ctx, "UserRepository", "Delete")
-	defer span.End()
-	span.SetAttributes(attribute.String("user.id", id.String()))
+	err := r.Repository.Delete(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return ErrUserNotFound
+	}
+	return err
+}
 
-	err := r.db.WithContext(ctx).Delete(&entities.User{}, "id = ?", id).Error
-	if err != nil {
-		telemetry.RecordError(span, err)
+func (r *UserRepository) Restore(ctx context.Context, id uuid.UUID) error {
+	err := r.Repository.Restore(ctx, id)
+	switch {
+	case errors.Is(err, database.ErrNotFound):
+		return ErrUserNotFound
+	case errors.Is(err, database.ErrUniqueViolation):
+		return ErrUserAlreadyExists
 	}
 	return err
 }
+
+func (r *UserRepository) HardDelete(ctx context.Context, id uuid.UUID) error {
+	err := r.Repository.HardDelete(ctx, id)
+	if errors.Is(err, database.ErrNotFound) {
+		return ErrUserNotFound
+	}
+	return err
+}
+
+// FindDeletedBefore returns up to limit users soft-deleted before t, oldest first.
+func (r *UserRepository) FindDeletedBefore(ctx context.Context, t time.Time, limit int) ([]*entities.User, error) {
+	return r.Find(ctx, database.Query[entities.User]{
+		Where: []database.Specification[entities.User]{database.Where[entities.User]("deleted_at < ?", t)},
+		Sort:  []database.Sort{database.Asc("deleted_at")},
+		Limit: limit,
+		Scope: database.OnlyDeleted,
+	})
+}
+
+// UserFilter narrows a user listing. Zero values are ignored.
+type UserFilter struct {
+	Name          string
+	Email         string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Trashed selects soft-deleted users instead of live ones.
+	Trashed bool
+}
+
+func (f UserFilter) specifications() []database.Specification[entities.User] {
+	var specs []database.Specification[entities.User]
+	if f.Trashed {
+		specs = append(specs, database.Deleted[entities.User]())
+	}
+	if f.Name != "" {
+		specs = append(specs, database.Where[entities.User]("name ILIKE ?", containsPattern(f.Name)))
+	}
+	if f.Email != "" {
+		specs = append(specs, database.Where[entities.User]("email ILIKE ?", containsPattern(f.Email)))
+	}
+	if f.CreatedAfter != nil {
+		specs = append(specs, database.Where[entities.User]("created_at >= ?", *f.CreatedAfter))
+	}
+	if f.CreatedBefore != nil {
+		specs = append(specs, database.Where[entities.User]("created_at < ?", *f.CreatedBefore))
+	}
+	return specs
+}
+
+// key identifies the filter in page cursors, so that a cursor is not
+// reused with other filters.
+func (f UserFilter) key() string {
+	data, _ := json.Marshal(f)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// userKeyset whitelists the columns users can be sorted and paginated by.
+var userKeyset = database.Keyset[entities.User]{
+	IDColumn: "id",
+	ID:       func(u *entities.User) string { return u.ID.String() },
+	Fields: map[string]database.SortField[entities.User]{
+		"created_at": {
+			Column: "created_at",
+			Value:  func(u *entities.User) string { return u.CreatedAt.UTC().Format(time.RFC3339Nano) },
+			Parse: func(s string) (any, error) {
+				return time.Parse(time.RFC3339Nano, s)
+			},
+		},
+		"name": {
+			Column: "name",
+			Value:  func(u *entities.User) string { return u.Name },
+		},
+		"email": {
+			Column: "email",
+			Value:  func(u *entities.User) string { return u.Email },
+		},
+	},
+}
+
+// ListPage returns a keyset-paginated page of users matching filter.
+func (r *UserRepository) ListPage(ctx context.Context, filter UserFilter, sort, cursor string, limit int) (database.Page[entities.User], error) {
+	return r.FindPage(ctx, userKeyset, database.PageQuery[entities.User]{
+		Where:  filter.specifications(),
+		Sort:   sort,
+		Filter: filter.key(),
+		Cursor: cursor,
+		Limit:  limit,
+	})
+}
+
+// ListPageAt returns the page of users matching filter that starts at offset.
+func (r *UserRepository) ListPageAt(ctx context.Context, filter UserFilter, sort string, offset, limit int) (database.Page[entities.User], error) {
+	return r.FindPageAt(ctx, userKeyset, database.PageQuery[entities.User]{
+		Where: filter.specifications(),
+		Sort:  sort,
+		Limit: limit,
+	}, offset)
+}
+
+// CountMatching returns the number of users matching filter.
+func (r *UserRepository) CountMatching(ctx context.Context, filter UserFilter) (int64, error) {
+	return r.Count(ctx, database.Query[entities.User]{Where: filter.specifications()})
+}
+
+// containsPattern builds an ILIKE pattern matching s anywhere,
+// escaping the LIKE wildcards it may contain.
+func containsPattern(s string) string {
+	return "%" + likeEscaper.Replace(s) + "%"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
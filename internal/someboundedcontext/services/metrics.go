@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/pkg/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// UserMetrics are the business metrics of the users context.
+type UserMetrics struct {
+	Created *telemetry.Counter
+	Fetched *telemetry.Counter
+	// Failed counts user operations that returned an error, by operation
+	// and error kind.
+	Failed *telemetry.Counter
+}
+
+func NewUserMetrics(registry *telemetry.Registry) (*UserMetrics, error) {
+	created, err := registry.Counter("app.users.created.total", "Total number of users created", "{user}")
+	if err != nil {
+		return nil, err
+	}
+	fetched, err := registry.Counter("app.users.fetched.total", "Total number of users returned by get and list operations", "{user}")
+	if err != nil {
+		return nil, err
+	}
+	failed, err := registry.Counter("app.users.failed.total", "Total number of failed user operations", "{operation}")
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserMetrics{
+		Created: created,
+		Fetched: fetched,
+		Failed:  failed,
+	}, nil
+}
+
+// recordFailure counts err, if any, as a failure of operation.
+func (m *UserMetrics) recordFailure(ctx context.Context, operation string, err error) {
+	if err == nil {
+		return
+	}
+	m.Failed.Inc(ctx,
+		attribute.String("operation", operation),
+		attribute.String("error.kind", apperrors.KindOf(err).String()),
+	)
+}
@@ -0,0 +1,12 @@
+package services
+
+// Permissions of the users context, granted to roles by auth.roles.
+// PermissionReadUsers and PermissionWriteUsers also exist with an ":own"
+// suffix, which limits them to the principal's own record.
+const (
+	PermissionReadUsers  = "users:read"
+	PermissionWriteUsers = "users:write"
+	// PermissionAdminUsers covers deleted users: listing, restoring and
+	// erasing them.
+	PermissionAdminUsers = "users:admin"
+)
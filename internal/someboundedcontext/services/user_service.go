@@ -4,47 +4,69 @@ import (
 	"context"
 	"errors"
 	"log/slog"
+	apperrors "project_template/internal/shared/errors"
 	"project_template/internal/shared/events"
 	"project_template/internal/someboundedcontext/config"
 	"project_template/internal/someboundedcontext/dto"
 	"project_template/internal/someboundedcontext/entities"
 	"project_template/internal/someboundedcontext/repositories"
+	"project_template/pkg/auth"
+	"project_template/pkg/database"
+	"project_template/pkg/logger"
 	"project_template/pkg/messagebus"
 	"project_template/pkg/telemetry"
+	"time"
 
 	"github.com/google/uuid"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound  = apperrors.New(apperrors.KindNotFound, "user_not_found", "user not found")
+	ErrInvalidCursor = apperrors.New(apperrors.KindInvalid, "invalid_cursor", "invalid cursor")
+	ErrInvalidSort   = apperrors.New(apperrors.KindInvalid, "invalid_sort", "invalid sort")
+	ErrEmailTaken    = apperrors.New(apperrors.KindConflict, "email_taken", "email already in use")
 )
 
+const defaultUsersSort = "-created_at"
+
 type UserService struct {
 	logger     *slog.Logger
 	config     config.Config
 	repository *repositories.UserRepository
 	publisher  messagebus.Publisher
+	metrics    *UserMetrics
+	authorizer *auth.Authorizer
 }
 
-func NewUserService(logger *slog.Logger, config config.Config, repository *repositories.UserRepository, publisher messagebus.Publisher) *UserService {
+func NewUserService(logger *slog.Logger, config config.Config, repository *repositories.UserRepository, publisher messagebus.Publisher, metrics *UserMetrics, authorizer *auth.Authorizer) *UserService {
 	return &UserService{
 		logger:     logger,
 		config:     config,
 		repository: repository,
 		publisher:  publisher,
+		metrics:    metrics,
+		authorizer: authorizer,
 	}
 }
 
-func (s *UserService) GetUser(ctx context.Context, id string) (dto.UserResponse, error) {
+func (s *UserService) GetUser(ctx context.Context, id string) (_ dto.UserResponse, err error) {
 	ctx, span := telemetry.StartServiceSpan(ctx, "UserService", "GetUser")
 	defer span.End()
+	defer func() { s.metrics.recordFailure(ctx, "GetUser", err) }()
 	span.SetAttributes(attribute.String("user.id", id))
 
 	uid, err := uuid.Parse(id)
 	if err != nil {
 		return dto.UserResponse{}, ErrUserNotFound
 	}
+	ctx = logger.With(ctx, "id", uid)
+
+	// Users are identified by the subject of their token.
+	if err := s.authorizer.RequireOwner(ctx, PermissionReadUsers, uid.String()); err != nil {
+		return dto.UserResponse{}, err
+	}
 
 	user, err := s.repository.GetByID(ctx, uid)
 	if err != nil {
@@ -52,10 +74,11 @@ func (s *UserService) GetUser(ctx context.Context, id string) (dto.UserResponse,
 			return dto.UserResponse{}, ErrUserNotFound
 		}
 		telemetry.RecordError(span, err)
-		s.logger.Error("failed to get user", "error", err, "id", uid)
+		s.logger.ErrorContext(ctx, "failed to get user", "error", err)
 		return dto.UserResponse{}, err
 	}
 
+	s.metrics.Fetched.Inc(ctx)
 	return dto.UserResponse{
 		ID:    user.ID,
 		Name:  user.Name,
@@ -63,33 +86,78 @@ func (s *UserService) GetUser(ctx context.Context, id string) (dto.UserResponse,
 	}, nil
 }
 
-func (s *UserService) ListUsers(ctx context.Context) (dto.UsersListResponse, error) {
+func (s *UserService) ListUsers(ctx context.Context, req dto.ListUsersRequest) (_ dto.UsersPageResponse, err error) {
 	ctx, span := telemetry.StartServiceSpan(ctx, "UserService", "ListUsers")
 	defer span.End()
+	defer func() { s.metrics.recordFailure(ctx, "ListUsers", err) }()
 
-	users, err := s.repository.GetAll(ctx)
+	sort := req.Sort
+	if sort == "" {
+		sort = defaultUsersSort
+	}
+	filter := repositories.UserFilter{
+		Name:          req.Name,
+		Email:         req.Email,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		Trashed:       req.Trashed,
+	}
+
+	var page database.Page[entities.User]
+	if req.Offset != nil {
+		page, err = s.repository.ListPageAt(ctx, filter, sort, *req.Offset, req.Limit)
+	} else {
+		page, err = s.repository.ListPage(ctx, filter, sort, req.Cursor, req.Limit)
+	}
 	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrInvalidCursor):
+			return dto.UsersPageResponse{}, ErrInvalidCursor
+		case errors.Is(err, database.ErrInvalidSort):
+			return dto.UsersPageResponse{}, ErrInvalidSort
+		}
 		telemetry.RecordError(span, err)
-		s.logger.Error("failed to list users", "error", err)
-		return nil, err
+		s.logger.ErrorContext(ctx, "failed to list users", "error", err)
+		return dto.UsersPageResponse{}, err
 	}
 
-	span.SetAttributes(attribute.Int("users.count", len(users)))
-	response := make(dto.UsersListResponse, len(users))
-	for i, user := range users {
-		response[i] = dto.UserResponse{
+	span.SetAttributes(attribute.Int("users.count", len(page.Items)))
+	response := dto.UsersPageResponse{
+		Data:       make(dto.UsersListResponse, len(page.Items)),
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		NextOffset: page.NextOffset,
+		PrevOffset: page.PrevOffset,
+	}
+	for i, user := range page.Items {
+		response.Data[i] = dto.UserResponse{
 			ID:    user.ID,
 			Name:  user.Name,
 			Email: user.Email,
 		}
+		if user.DeletedAt.Valid {
+			response.Data[i].DeletedAt = &user.DeletedAt.Time
+		}
 	}
 
+	if req.IncludeTotal {
+		total, err := s.repository.CountMatching(ctx, filter)
+		if err != nil {
+			telemetry.RecordError(span, err)
+			s.logger.ErrorContext(ctx, "failed to count users", "error", err)
+			return dto.UsersPageResponse{}, err
+		}
+		response.Total = &total
+	}
+
+	s.metrics.Fetched.Add(ctx, int64(len(page.Items)))
 	return response, nil
 }
 
-func (s *UserService) CreateUser(ctx context.Context, user dto.CreateUserRequest) (dto.UserResponse, error) {
+func (s *UserService) CreateUser(ctx context.Context, user dto.CreateUserRequest) (_ dto.UserResponse, err error) {
 	ctx, span := telemetry.StartServiceSpan(ctx, "UserService", "CreateUser")
 	defer span.End()
+	defer func() { s.metrics.recordFailure(ctx, "CreateUser", err) }()
 	span.SetAttributes(
 		attribute.String("user.name", user.Name),
 		attribute.String("user.email", user.Email),
@@ -100,10 +168,13 @@ func (s *UserService) CreateUser(ctx context.Context, user dto.CreateUserRequest
 		Email: user.Email,
 	}
 
-	err := s.repository.Create(ctx, newUser)
+	err = s.repository.Create(ctx, newUser)
 	if err != nil {
+		if errors.Is(err, repositories.ErrUserAlreadyExists) {
+			return dto.UserResponse{}, ErrEmailTaken
+		}
 		telemetry.RecordError(span, err)
-		s.logger.Error("failed to create user", "error", err)
+		s.logger.ErrorContext(ctx, "failed to create user", "error", err)
 		return dto.UserResponse{}, err
 	}
 
@@ -113,8 +184,9 @@ func (s *UserService) CreateUser(ctx context.Context, user dto.CreateUserRequest
 		Email:  newUser.Email,
 	}
 	if err := s.publisher.Publish(ctx, event); err != nil {
-		s.logger.Error("failed to publish UserCreatedEvent", "error", err, "user_id", newUser.ID)
+		s.logger.ErrorContext(ctx, "failed to publish UserCreatedEvent", "error", err, "user_id", newUser.ID)
 	}
+	s.metrics.Created.Inc(ctx)
 
 	span.SetAttributes(attribute.String("user.id", newUser.ID.String()))
 	return dto.UserResponse{
@@ -123,3 +195,232 @@ func (s *UserService) CreateUser(ctx context.Context, user dto.CreateUserRequest
 		Email: newUser.Email,
 	}, nil
 }
+
+func (s *UserService) UpdateUser(ctx context.Context, id string, req dto.UpdateUserRequest) (_ dto.UserResponse, err error) {
+	ctx, span := telemetry.StartServiceSpan(ctx, "UserService", "UpdateUser")
+	defer span.End()
+	defer func() { s.metrics.recordFailure(ctx, "UpdateUser", err) }()
+	span.SetAttributes(attribute.String("user.id", id))
+
+	user, err := s.loadForUpdate(ctx, span, id)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	return s.update(ctx, span, user, req)
+}
+
+// PatchUser updates a user with the request that patch derives from the
+// current one. It requires write permission only: the current state is
+// read for the update, not returned to the caller as a fetch.
+func (s *UserService) PatchUser(ctx context.Context, id string, patch func(dto.UpdateUserRequest) (dto.UpdateUserRequest, error)) (_ dto.UserResponse, err error) {
+	ctx, span := telemetry.StartServiceSpan(ctx, "UserService", "PatchUser")
+	defer span.End()
+	defer func() { s.metrics.recordFailure(ctx, "PatchUser", err) }()
+	span.SetAttributes(attribute.String("user.id", id))
+
+	user, err := s.loadForUpdate(ctx, span, id)
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	req, err := patch(dto.UpdateUserRequest{Name: user.Name, Email: user.Email})
+	if err != nil {
+		return dto.UserResponse{}, err
+	}
+	return s.update(ctx, span, user, req)
+}
+
+// loadForUpdate checks that the caller may write the user and loads it.
+func (s *UserService) loadForUpdate(ctx context.Context, span trace.Span, id string) (*entities.User, error) {
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return nil, ErrUserNotFound
+	}
+	ctx = logger.With(ctx, "id", uid)
+
+	if err := s.authorizer.RequireOwner(ctx, PermissionWriteUsers, uid.String()); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repository.GetByID(ctx, uid)
+	if err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return nil, ErrUserNotFound
+		}
+		telemetry.RecordError(span, err)
+		s.logger.ErrorContext(ctx, "failed to get user", "error", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *UserService) update(ctx context.Context, span trace.Span, user *entities.User, req dto.UpdateUserRequest) (dto.UserResponse, error) {
+	ctx = logger.With(ctx, "id", user.ID)
+
+	if req.Email != user.Email {
+		existing, err := s.repository.GetByEmail(ctx, req.Email)
+		switch {
+		case err == nil && existing.ID != user.ID:
+			return dto.UserResponse{}, ErrEmailTaken
+		case err != nil && !errors.Is(err, repositories.ErrUserNotFound):
+			telemetry.RecordError(span, err)
+			s.logger.ErrorContext(ctx, "failed to look up user by email", "error", err)
+			return dto.UserResponse{}, err
+		}
+	}
+
+	user.Name = req.Name
+	user.Email = req.Email
+	if err := s.repository.Update(ctx, user); err != nil {
+		if errors.Is(err, repositories.ErrUserAlreadyExists) {
+			return dto.UserResponse{}, ErrEmailTaken
+		}
+		telemetry.RecordError(span, err)
+		s.logger.ErrorContext(ctx, "failed to update user", "error", err)
+		return dto.UserResponse{}, err
+	}
+
+	event := events.UserUpdatedEvent{
+		UserID: user.ID,
+		Name:   user.Name,
+		Email:  user.Email,
+	}
+	if err := s.publisher.Publish(ctx, event); err != nil {
+		s.logger.ErrorContext(ctx, "failed to publish UserUpdatedEvent", "error", err)
+	}
+
+	return dto.UserResponse{
+		ID:    user.ID,
+		Name:  user.Name,
+		Email: user.Email,
+	}, nil
+}
+
+func (s *UserService) DeleteUser(ctx context.Context, id string) (err error) {
+	ctx, span := telemetry.StartServiceSpan(ctx, "UserService", "DeleteUser")
+	defer span.End()
+	defer func() { s.metrics.recordFailure(ctx, "DeleteUser", err) }()
+	span.SetAttributes(attribute.String("user.id", id))
+
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	ctx = logger.With(ctx, "id", uid)
+
+	if err := s.authorizer.RequireOwner(ctx, PermissionWriteUsers, uid.String()); err != nil {
+		return err
+	}
+
+	if err := s.repository.Delete(ctx, uid); err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		telemetry.RecordError(span, err)
+		s.logger.ErrorContext(ctx, "failed to delete user", "error", err)
+		return err
+	}
+
+	if err := s.publisher.Publish(ctx, events.UserDeletedEvent{UserID: uid}); err != nil {
+		s.logger.ErrorContext(ctx, "failed to publish UserDeletedEvent", "error", err)
+	}
+
+	return nil
+}
+
+func (s *UserService) RestoreUser(ctx context.Context, id string) (_ dto.UserResponse, err error) {
+	ctx, span := telemetry.StartServiceSpan(ctx, "UserService", "RestoreUser")
+	defer span.End()
+	defer func() { s.metrics.recordFailure(ctx, "RestoreUser", err) }()
+	span.SetAttributes(attribute.String("user.id", id))
+
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return dto.UserResponse{}, ErrUserNotFound
+	}
+	ctx = logger.With(ctx, "id", uid)
+
+	if err := s.repository.Restore(ctx, uid); err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrUserNotFound):
+			return dto.UserResponse{}, ErrUserNotFound
+		case errors.Is(err, repositories.ErrUserAlreadyExists):
+			return dto.UserResponse{}, ErrEmailTaken
+		}
+		telemetry.RecordError(span, err)
+		s.logger.ErrorContext(ctx, "failed to restore user", "error", err)
+		return dto.UserResponse{}, err
+	}
+
+	user, err := s.repository.GetByID(ctx, uid)
+	if err != nil {
+		telemetry.RecordError(span, err)
+		s.logger.ErrorContext(ctx, "failed to get restored user", "error", err)
+		return dto.UserResponse{}, err
+	}
+
+	return dto.UserResponse{
+		ID:    user.ID,
+		Name:  user.Name,
+		Email: user.Email,
+	}, nil
+}
+
+// EraseUser permanently removes a user, live or soft-deleted, and publishes
+// UserErasedEvent so that other bounded contexts can drop their copies.
+func (s *UserService) EraseUser(ctx context.Context, id string) (err error) {
+	ctx, span := telemetry.StartServiceSpan(ctx, "UserService", "EraseUser")
+	defer span.End()
+	defer func() { s.metrics.recordFailure(ctx, "EraseUser", err) }()
+	span.SetAttributes(attribute.String("user.id", id))
+
+	uid, err := uuid.Parse(id)
+	if err != nil {
+		return ErrUserNotFound
+	}
+	ctx = logger.With(ctx, "id", uid)
+
+	if err := s.repository.HardDelete(ctx, uid); err != nil {
+		if errors.Is(err, repositories.ErrUserNotFound) {
+			return ErrUserNotFound
+		}
+		telemetry.RecordError(span, err)
+		s.logger.ErrorContext(ctx, "failed to erase user", "error", err)
+		return err
+	}
+
+	if err := s.publisher.Publish(ctx, events.UserErasedEvent{UserID: uid}); err != nil {
+		s.logger.ErrorContext(ctx, "failed to publish UserErasedEvent", "error", err)
+	}
+
+	return nil
+}
+
+// PurgeDeletedUsers erases up to batchSize users that were soft-deleted
+// before cutoff and returns how many were erased.
+func (s *UserService) PurgeDeletedUsers(ctx context.Context, cutoff time.Time, batchSize int) (_ int, err error) {
+	ctx, span := telemetry.StartServiceSpan(ctx, "UserService", "PurgeDeletedUsers")
+	defer span.End()
+	defer func() { s.metrics.recordFailure(ctx, "PurgeDeletedUsers", err) }()
+
+	users, err := s.repository.FindDeletedBefore(ctx, cutoff, batchSize)
+	if err != nil {
+		telemetry.RecordError(span, err)
+		s.logger.ErrorContext(ctx, "failed to find deleted users", "error", err)
+		return 0, err
+	}
+
+	erased := 0
+	for _, user := range users {
+		if err := s.EraseUser(ctx, user.ID.String()); err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				continue
+			}
+			telemetry.RecordError(span, err)
+			return erased, err
+		}
+		erased++
+	}
+
+	span.SetAttributes(attribute.Int("users.erased", erased))
+	return erased, nil
+}
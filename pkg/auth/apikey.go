@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/pkg/database"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// apiKeyPrefix starts every API key so that keys are recognizable, e.g.
+// by secret scanners.
+const apiKeyPrefix = "ak_"
+
+var (
+	ErrInvalidAPIKey  = apperrors.New(apperrors.KindUnauthorized, "invalid_api_key", "invalid API key")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+)
+
+// APIKey authenticates a machine client. Only the SHA-256 hash of the key
+// is stored; the key itself is shown once, when it is created.
+type APIKey struct {
+	ID   uuid.UUID `json:"id" gorm:"type:uuid;primaryKey;default:gen_random_uuid()"`
+	Name string    `json:"name" gorm:"not null"`
+	// Prefix is the start of the key, to tell keys apart in listings.
+	Prefix      string     `json:"prefix" gorm:"not null"`
+	Hash        string     `json:"-" gorm:"column:key_hash;not null"`
+	Permissions []string   `json:"permissions" gorm:"type:jsonb;serializer:json;not null"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RevokedAt   *time.Time `json:"revoked_at,omitempty"`
+	LastUsedAt  *time.Time `json:"last_used_at,omitempty"`
+}
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == uuid.Nil {
+		k.ID = uuid.New()
+	}
+	return nil
+}
+
+// Active reports whether the key is neither revoked nor expired at t.
+func (k *APIKey) Active(t time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || t.Before(*k.ExpiresAt))
+}
+
+// Principal returns the principal requests made with the key act as.
+func (k *APIKey) Principal() *Principal {
+	return &Principal{
+		Subject:     "apikey:" + k.ID.String(),
+		Permissions: k.Permissions,
+		Claims: map[string]any{
+			"api_key_id":   k.ID.String(),
+			"api_key_name": k.Name,
+		},
+	}
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+type APIKeyRepository struct {
+	*database.Repository[APIKey, uuid.UUID]
+}
+
+func NewAPIKeyRepository(db *gorm.DB) *APIKeyRepository {
+	return &APIKeyRepository{
+		Repository: database.NewRepository[APIKey, uuid.UUID](db, "APIKeyRepository"),
+	}
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, hash string) (*APIKey, error) {
+	key, err := r.FindOne(ctx, database.Query[APIKey]{
+		Where: []database.Specification[APIKey]{database.Eq[APIKey]("key_hash", hash)},
+	})
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, ErrAPIKeyNotFound
+	}
+	return key, err
+}
+
+// List returns all keys, including revoked and expired ones, newest first.
+func (r *APIKeyRepository) List(ctx context.Context) ([]*APIKey, error) {
+	return r.Find(ctx, database.Query[APIKey]{
+		Sort: []database.Sort{database.Desc("created_at")},
+	})
+}
+
+// Revoke marks the key as revoked. Revoking a revoked key is a no-op.
+func (r *APIKeyRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := r.DB(ctx).Model(&APIKey{}).Where("id = ?", id).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, ?)", at))
+	if result.Error != nil {
+		return database.TranslateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) TouchLastUsed(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.DB(ctx).Model(&APIKey{}).Where("id = ?", id).Update("last_used_at", at).Error
+}
+
+// APIKeys issues, revokes and authenticates API keys. Authenticated keys
+// are cached for APIKeyConfig.CacheTTL, so a key revoked by another
+// instance, or by the CLI, is honoured here once its entry expires.
+type APIKeys struct {
+	config     APIKeyConfig
+	repository *APIKeyRepository
+	logger     *slog.Logger
+
+	mu    sync.Mutex
+	cache map[string]*cachedAPIKey
+}
+
+type cachedAPIKey struct {
+	key      *APIKey
+	loadedAt time.Time
+	// touchedAt is when last_used_at was last written.
+	touchedAt time.Time
+}
+
+func NewAPIKeys(config Config, repository *APIKeyRepository, logger *slog.Logger) *APIKeys {
+	return &APIKeys{
+		config:     config.APIKeys,
+		repository: repository,
+		logger:     logger,
+		cache:      make(map[string]*cachedAPIKey),
+	}
+}
+
+// Create issues a key granting permissions and returns it with its record.
+// A zero ttl creates a key that does not expire.
+func (s *APIKeys) Create(ctx context.Context, name string, permissions []string, ttl time.Duration) (string, *APIKey, error) {
+	if name == "" {
+		return "", nil, errors.New("API key name is required")
+	}
+	if len(permissions) == 0 {
+		return "", nil, errors.New("API key needs at least one permission")
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", nil, err
+	}
+	plain := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	record := &APIKey{
+		Name:        name,
+		Prefix:      plain[:len(apiKeyPrefix)+8],
+		Hash:        hashAPIKey(plain),
+		Permissions: permissions,
+	}
+	if ttl > 0 {
+		expires := time.Now().Add(ttl)
+		record.ExpiresAt = &expires
+	}
+	if err := s.repository.Create(ctx, record); err != nil {
+		return "", nil, fmt.Errorf("failed to store API key: %w", err)
+	}
+	return plain, record, nil
+}
+
+// Revoke revokes the key with the given ID.
+func (s *APIKeys) Revoke(ctx context.Context, id uuid.UUID) error {
+	if err := s.repository.Revoke(ctx, id, time.Now()); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, entry := range s.cache {
+		if entry.key.ID == id {
+			delete(s.cache, hash)
+		}
+	}
+	return nil
+}
+
+func (s *APIKeys) List(ctx context.Context) ([]*APIKey, error) {
+	return s.repository.List(ctx)
+}
+
+// Authenticate returns the active key matching plain, or ErrInvalidAPIKey.
+// Unknown keys are not cached, so that guessing cannot fill the cache.
+func (s *APIKeys) Authenticate(ctx context.Context, plain string) (*APIKey, error) {
+	if !strings.HasPrefix(plain, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+	hash := hashAPIKey(plain)
+	now := time.Now()
+
+	s.mu.Lock()
+	entry, ok := s.cache[hash]
+	if ok && now.Sub(entry.loadedAt) >= s.config.CacheTTL {
+		ok = false
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		key, err := s.repository.GetByHash(ctx, hash)
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			return nil, ErrInvalidAPIKey
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up API key: %w", err)
+		}
+
+		s.mu.Lock()
+		touchedAt := time.Time{}
+		if old := s.cache[hash]; old != nil {
+			touchedAt = old.touchedAt
+		}
+		entry = &cachedAPIKey{key: key, loadedAt: now, touchedAt: touchedAt}
+		s.cache[hash] = entry
+		s.mu.Unlock()
+	}
+
+	if !entry.key.Active(now) {
+		return nil, ErrInvalidAPIKey
+	}
+	s.touch(ctx, entry, now)
+	return entry.key, nil
+}
+
+// touch records that the key was used. last_used_at is written at most
+// once per TouchInterval, outside of the request.
+func (s *APIKeys) touch(ctx context.Context, entry *cachedAPIKey, now time.Time) {
+	s.mu.Lock()
+	if now.Sub(entry.touchedAt) < s.config.TouchInterval {
+		s.mu.Unlock()
+		return
+	}
+	entry.touchedAt = now
+	s.mu.Unlock()
+
+	id := entry.key.ID
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		if err := s.repository.TouchLastUsed(ctx, id, now); err != nil {
+			s.logger.WarnContext(ctx, "failed to record API key usage", "api_key_id", id, "error", err)
+		}
+	}()
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"project_template/pkg/logger"
+	"project_template/pkg/telemetry"
+	"project_template/pkg/webserver"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewAPIKeyHeader provides the API key header to the authentication
+// failure limit, which counts only requests carrying credentials.
+func NewAPIKeyHeader(config Config) webserver.CredentialHeader {
+	if !config.Enabled || !config.APIKeys.Enabled {
+		return ""
+	}
+	return webserver.CredentialHeader(config.APIKeys.Header)
+}
+
+// NewAPIKeyMiddleware authenticates requests that carry an API key in
+// APIKeyConfig.Header and puts the key's principal in the request context,
+// where the guard and the Authorizer treat it like a token's. Requests
+// already authenticated by a bearer token are left alone. Every request
+// with a key is counted in auth.api_key.requests.
+func NewAPIKeyMiddleware(config Config, keys *APIKeys, registry *telemetry.Registry, log *slog.Logger) (webserver.Middleware, error) {
+	if !config.Enabled || !config.APIKeys.Enabled {
+		return func(next http.Handler) http.Handler { return next }, nil
+	}
+
+	requests, err := registry.Counter("auth.api_key.requests", "Requests authenticated with an API key", "{request}")
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			plain := r.Header.Get(config.APIKeys.Header)
+			if plain == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			ctx := r.Context()
+			if _, ok := FromContext(ctx); ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			key, err := keys.Authenticate(ctx, plain)
+			if err != nil {
+				if errors.Is(err, ErrInvalidAPIKey) {
+					requests.Inc(ctx, attribute.String("result", "rejected"))
+					log.DebugContext(ctx, "rejected API key")
+				}
+				webserver.WriteError(w, r, log, err)
+				return
+			}
+			requests.Inc(ctx,
+				attribute.String("result", "accepted"),
+				attribute.String("api_key.name", key.Name),
+			)
+
+			principal := key.Principal()
+			ctx = NewContext(ctx, principal)
+			ctx = logger.WithUserID(ctx, principal.Subject)
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("enduser.id", principal.Subject))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}, nil
+}
@@ -0,0 +1,71 @@
+package auth
+
+import (
+	"errors"
+	"time"
+)
+
+type Config struct {
+	// Enabled turns on bearer token validation. While it is off, routes
+	// that require authentication can be called without it.
+	Enabled bool `mapstructure:"enabled" default:"false"`
+
+	// The signing keys are read from JWKSFile, from JWKSURL, or else from
+	// the jwks_uri that the OpenID Connect discovery document of Issuer
+	// points to. JWKS URLs are fetched with the "auth" httpclient upstream.
+	JWKSFile string `mapstructure:"jwks_file"`
+	JWKSURL  string `mapstructure:"jwks_url"`
+	// RefreshInterval is how often the keys are reloaded to pick up
+	// rotated ones. A token signed with an unknown key triggers an earlier
+	// reload, at most once per MinRefreshInterval.
+	RefreshInterval    time.Duration `mapstructure:"refresh_interval" default:"15m"`
+	MinRefreshInterval time.Duration `mapstructure:"min_refresh_interval" default:"1m"`
+
+	// Issuer and Audience, if set, must match the iss and aud claims.
+	Issuer   string `mapstructure:"issuer"`
+	Audience string `mapstructure:"audience"`
+	// Algorithms are the accepted signing algorithms.
+	Algorithms []string `mapstructure:"algorithms" default:"[\"RS256\",\"ES256\",\"EdDSA\"]"`
+	// Leeway tolerates clock skew when checking exp, nbf and iat.
+	Leeway time.Duration `mapstructure:"leeway" default:"30s"`
+	// RolesClaim holds the principal's roles. Nested claims are addressed
+	// with a dotted path such as realm_access.roles.
+	RolesClaim string `mapstructure:"roles_claim" default:"roles"`
+
+	// Roles maps each role to the permissions it grants. A permission
+	// ending in "*" grants all permissions with that prefix, e.g. "users:*".
+	Roles map[string][]string `mapstructure:"roles" default:"{\"admin\":[\"*\"]}"`
+
+	// RequireByDefault requires authentication on routes that do not
+	// declare whether they need it.
+	RequireByDefault bool `mapstructure:"require_by_default" default:"false"`
+
+	APIKeys APIKeyConfig `mapstructure:"api_keys"`
+}
+
+// APIKeyConfig configures API keys for machine clients. They are accepted
+// only while authentication is enabled.
+type APIKeyConfig struct {
+	Enabled bool `mapstructure:"enabled" default:"true"`
+	// Header carries the key.
+	Header string `mapstructure:"header" default:"X-API-Key"`
+	// CacheTTL is how long a key is trusted without reading it again, and
+	// so how long a revocation can take to reach other instances.
+	CacheTTL time.Duration `mapstructure:"cache_ttl" default:"1m"`
+	// TouchInterval limits how often a key's last use is written.
+	TouchInterval time.Duration `mapstructure:"touch_interval" default:"1m"`
+}
+
+func (c Config) validate() error {
+	switch {
+	case c.RefreshInterval <= 0:
+		return errors.New("refresh_interval must be positive")
+	case c.MinRefreshInterval < 0:
+		return errors.New("min_refresh_interval must not be negative")
+	case c.Leeway < 0:
+		return errors.New("leeway must not be negative")
+	case len(c.Algorithms) == 0:
+		return errors.New("algorithms must not be empty")
+	}
+	return nil
+}
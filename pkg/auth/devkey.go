@@ -0,0 +1,116 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json/v2"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DevKey signs tokens for local development and testing, so that the
+// application can be run with authentication enabled without an identity
+// provider. Point Config.JWKSFile at the file written by WriteJWKS.
+type DevKey struct {
+	key *ecdsa.PrivateKey
+	kid string
+}
+
+// LoadOrCreateDevKey reads the P-256 private key at path, creating it if
+// the file does not exist.
+func LoadOrCreateDevKey(path string) (*DevKey, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return createDevKey(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := parsed.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not hold an ECDSA key", path)
+	}
+	return newDevKey(key)
+}
+
+func createDevKey(path string) (*DevKey, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, err
+	}
+	return newDevKey(key)
+}
+
+func newDevKey(key *ecdsa.PrivateKey) (*DevKey, error) {
+	pub, err := key.PublicKey.ECDH()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(pub.Bytes())
+	return &DevKey{key: key, kid: base64.RawURLEncoding.EncodeToString(sum[:8])}, nil
+}
+
+// WriteJWKS writes the JWKS holding the public key to path.
+func (k *DevKey) WriteJWKS(path string) error {
+	pub, err := k.key.PublicKey.ECDH()
+	if err != nil {
+		return err
+	}
+	// The uncompressed point is 0x04 followed by X and Y.
+	point := pub.Bytes()[1:]
+	size := len(point) / 2
+
+	data, err := json.Marshal(map[string]any{
+		"keys": []jwk{{
+			Kty: "EC",
+			Kid: k.kid,
+			Use: "sig",
+			Crv: "P-256",
+			X:   base64.RawURLEncoding.EncodeToString(point[:size]),
+			Y:   base64.RawURLEncoding.EncodeToString(point[size:]),
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// Sign returns an ES256 token with the given claims.
+func (k *DevKey) Sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+	token.Header["kid"] = k.kid
+	return token.SignedString(k.key)
+}
@@ -0,0 +1,284 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json/v2"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"project_template/pkg/httpclient"
+)
+
+var errUnknownKey = errors.New("unknown signing key")
+
+// KeySet holds the public keys tokens are verified with. It is reloaded
+// periodically and when a token names a key it does not know, so that
+// keys rotated by the issuer are picked up without a restart.
+type KeySet struct {
+	config Config
+	client *httpclient.Client
+	logger *slog.Logger
+
+	mu   sync.RWMutex
+	keys map[string]crypto.PublicKey
+
+	// loadMu serializes loads and guards lastLoad and jwksURL.
+	loadMu   sync.Mutex
+	lastLoad time.Time
+	jwksURL  string
+}
+
+func NewKeySet(config Config, clients *httpclient.Clients, logger *slog.Logger) *KeySet {
+	return &KeySet{
+		config:  config,
+		client:  clients.Get("auth"),
+		logger:  logger,
+		keys:    make(map[string]crypto.PublicKey),
+		jwksURL: config.JWKSURL,
+	}
+}
+
+// Key returns the key with the given ID. A token without a key ID is
+// accepted if the set holds a single key.
+func (k *KeySet) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+	if err := k.load(ctx, k.config.MinRefreshInterval); err != nil {
+		k.logger.WarnContext(ctx, "failed to reload signing keys", "error", err)
+	}
+	if key, ok := k.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, errUnknownKey
+}
+
+func (k *KeySet) lookup(kid string) (crypto.PublicKey, bool) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+
+	if kid == "" && len(k.keys) == 1 {
+		for _, key := range k.keys {
+			return key, true
+		}
+	}
+	key, ok := k.keys[kid]
+	return key, ok
+}
+
+// load reloads the keys unless they were loaded less than minAge ago.
+func (k *KeySet) load(ctx context.Context, minAge time.Duration) error {
+	k.loadMu.Lock()
+	defer k.loadMu.Unlock()
+
+	if time.Since(k.lastLoad) < minAge {
+		return nil
+	}
+	k.lastLoad = time.Now()
+
+	data, err := k.fetch(ctx)
+	if err != nil {
+		return err
+	}
+	keys, err := parseJWKS(data)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	k.keys = keys
+	k.mu.Unlock()
+	k.logger.DebugContext(ctx, "loaded signing keys", "count", len(keys))
+	return nil
+}
+
+func (k *KeySet) fetch(ctx context.Context) ([]byte, error) {
+	if k.config.JWKSFile != "" {
+		data, err := os.ReadFile(k.config.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWKS file: %w", err)
+		}
+		return data, nil
+	}
+
+	if k.jwksURL == "" {
+		if k.config.Issuer == "" {
+			return nil, errors.New("no JWKS file, JWKS URL or issuer configured")
+		}
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		data, err := k.get(ctx, strings.TrimSuffix(k.config.Issuer, "/")+"/.well-known/openid-configuration")
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover JWKS URL: %w", err)
+		}
+		if err := json.Unmarshal(data, &discovery); err != nil {
+			return nil, fmt.Errorf("invalid OpenID Connect discovery document: %w", err)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, errors.New("OpenID Connect discovery document has no jwks_uri")
+		}
+		k.jwksURL = discovery.JWKSURI
+	}
+
+	data, err := k.get(ctx, k.jwksURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	return data, nil
+}
+
+func (k *KeySet) get(ctx context.Context, url string) ([]byte, error) {
+	req, err := k.client.NewRequest(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := k.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+}
+
+// refreshLoop reloads the keys every RefreshInterval until ctx is done.
+func (k *KeySet) refreshLoop(ctx context.Context) {
+	ticker := time.NewTicker(k.config.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := k.load(ctx, 0); err != nil {
+				k.logger.WarnContext(ctx, "failed to refresh signing keys", "error", err)
+			}
+		}
+	}
+}
+
+// jwk is a JSON Web Key (RFC 7517) holding an RSA, EC or Ed25519 public key.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid,omitempty"`
+	Use string `json:"use,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+// parseJWKS returns the signing keys of a JWKS document by key ID. Keys of
+// unsupported types are skipped.
+func parseJWKS(data []byte) (map[string]crypto.PublicKey, error) {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("invalid JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			return nil, fmt.Errorf("invalid key %q: %w", k.Kid, err)
+		}
+		if key != nil {
+			keys[k.Kid] = key
+		}
+	}
+	return keys, nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+
+	case "EC":
+		var curve elliptic.Curve
+		var ecdhCurve ecdh.Curve
+		switch k.Crv {
+		case "P-256":
+			curve, ecdhCurve = elliptic.P256(), ecdh.P256()
+		case "P-384":
+			curve, ecdhCurve = elliptic.P384(), ecdh.P384()
+		case "P-521":
+			curve, ecdhCurve = elliptic.P521(), ecdh.P521()
+		default:
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		// Parsing the uncompressed point rejects points off the curve.
+		if _, err := ecdhCurve.NewPublicKey(append(append([]byte{4}, x...), y...)); err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, nil
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+
+	default:
+		return nil, nil
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
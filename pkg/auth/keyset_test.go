@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json/v2"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestKeySetReloadsOnceForUnknownKey(t *testing.T) {
+	key := newTestKey(t)
+	jwks := jwksOf(t, key)
+	srv, hits := jwksServer(t, func() []byte { return jwks })
+
+	cfg := testConfig(t)
+	cfg.JWKSURL = srv.URL
+	cfg.MinRefreshInterval = time.Hour
+	keys := newTestKeySet(t, cfg)
+
+	for range 3 {
+		if _, err := keys.Key(context.Background(), "unknown"); !errors.Is(err, errUnknownKey) {
+			t.Fatalf("Key(unknown) = %v, want errUnknownKey", err)
+		}
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times, want 1", n)
+	}
+
+	if _, err := keys.Key(context.Background(), key.kid); err != nil {
+		t.Errorf("Key(%s) = %v, want the loaded key", key.kid, err)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("JWKS fetched %d times after a known key, want 1", n)
+	}
+}
+
+func TestParseJWKS(t *testing.T) {
+	key := newTestKey(t)
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := json.Unmarshal(jwksOf(t, key), &set); err != nil {
+		t.Fatal(err)
+	}
+	valid := set.Keys[0]
+
+	offCurve := valid
+	y, err := base64.RawURLEncoding.DecodeString(valid.Y)
+	if err != nil {
+		t.Fatal(err)
+	}
+	y = new(big.Int).Add(new(big.Int).SetBytes(y), big.NewInt(1)).FillBytes(make([]byte, len(y)))
+	offCurve.Y = base64.RawURLEncoding.EncodeToString(y)
+
+	encryption := valid
+	encryption.Use = "enc"
+	unsupported := jwk{Kty: "oct", Kid: "secret"}
+
+	tests := []struct {
+		name    string
+		keys    []jwk
+		want    int
+		wantErr bool
+	}{
+		{name: "valid EC key", keys: []jwk{valid}, want: 1},
+		{name: "off-curve EC point", keys: []jwk{offCurve}, wantErr: true},
+		{name: "encryption key skipped", keys: []jwk{encryption}},
+		{name: "unsupported key type skipped", keys: []jwk{unsupported, valid}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(map[string]any{"keys": tt.keys})
+			if err != nil {
+				t.Fatal(err)
+			}
+			keys, err := parseJWKS(data)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("parseJWKS accepted an invalid key")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(keys) != tt.want {
+				t.Errorf("parseJWKS returned %d keys, want %d", len(keys), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"project_template/pkg/logger"
+	"project_template/pkg/webserver"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Protected is implemented by routes that declare whether they require an
+// authenticated principal. Other routes follow Config.RequireByDefault.
+type Protected interface {
+	RequiresAuth() bool
+}
+
+// NewMiddleware authenticates requests that carry a bearer token and puts
+// their principal in the request context. Requests without a valid token
+// pass through anonymously; whether a route needs one is decided by the
+// guard, which rejects an invalid token on routes requiring authentication.
+// Routes that do not, such as the log level endpoints with their own admin
+// token, still receive the Authorization header.
+func NewMiddleware(config Config, verifier *Verifier, authorizer *Authorizer, log *slog.Logger) webserver.Middleware {
+	if !config.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, ok := bearerToken(r)
+			if !ok {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			principal, err := verifier.Verify(ctx, token)
+			if err != nil {
+				log.DebugContext(ctx, "rejected bearer token", "error", err)
+				next.ServeHTTP(w, r.WithContext(withTokenError(ctx, ErrInvalidToken)))
+				return
+			}
+
+			principal.Permissions = authorizer.Permissions(principal.Roles)
+			ctx = NewContext(ctx, principal)
+			ctx = logger.WithUserID(ctx, principal.Subject)
+			trace.SpanFromContext(ctx).SetAttributes(attribute.String("enduser.id", principal.Subject))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+type tokenErrorKey struct{}
+
+func withTokenError(ctx context.Context, err error) context.Context {
+	return context.WithValue(ctx, tokenErrorKey{}, err)
+}
+
+// tokenError returns why the bearer token of the request was rejected.
+func tokenError(ctx context.Context) error {
+	err, _ := ctx.Value(tokenErrorKey{}).(error)
+	return err
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+// Guard rejects requests to routes that require authentication without a
+// principal, and to routes that require permissions the principal lacks.
+type Guard struct {
+	config     Config
+	authorizer *Authorizer
+}
+
+func NewGuard(config Config, authorizer *Authorizer) *Guard {
+	return &Guard{config: config, authorizer: authorizer}
+}
+
+func (g *Guard) Check(w http.ResponseWriter, r *http.Request, route any) error {
+	if !g.config.Enabled {
+		return nil
+	}
+
+	var permissions []string
+	if p, ok := route.(Permissioned); ok {
+		permissions = p.RequiredPermissions()
+	}
+	if len(permissions) == 0 && !g.requiresAuth(route) {
+		return nil
+	}
+
+	if err := tokenError(r.Context()); err != nil {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+		return err
+	}
+	err := g.authorizer.Require(r.Context(), permissions...)
+	if errors.Is(err, ErrUnauthenticated) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	return err
+}
+
+func (g *Guard) requiresAuth(route any) bool {
+	if p, ok := route.(Protected); ok {
+		return p.RequiresAuth()
+	}
+	return g.config.RequireByDefault
+}
@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"project_template/pkg/logger"
+	"project_template/pkg/shutdown"
+	"project_template/pkg/webserver"
+
+	"go.uber.org/fx"
+)
+
+// Module provides bearer token and API key authentication. The middlewares
+// themselves are registered with the other middlewares by the application.
+var Module = fx.Module("auth",
+	logger.ForModule("auth"),
+	fx.Provide(
+		NewKeySet,
+		NewVerifier,
+		NewAuthorizer,
+		NewAPIKeyRepository,
+		NewAPIKeys,
+		webserver.AsGuard(NewGuard),
+		webserver.AsCredentialHeader(NewAPIKeyHeader),
+	),
+	fx.Invoke(startKeySet),
+)
+
+// startKeySet loads the signing keys and keeps them fresh. An invalid
+// configuration or a JWKS file that cannot be read fails startup; an
+// unreachable issuer does not, as keys are loaded again on the first token.
+// Refreshing stops only after the HTTP server has drained, as in-flight
+// requests still verify tokens.
+func startKeySet(lc fx.Lifecycle, config Config, keys *KeySet, logger *slog.Logger, coordinator *shutdown.Coordinator) error {
+	if !config.Enabled {
+		logger.Warn("Authentication disabled; routes requiring it are public")
+		return nil
+	}
+	if err := config.validate(); err != nil {
+		return fmt.Errorf("invalid auth config: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			if err := keys.load(ctx, 0); err != nil {
+				if config.JWKSFile != "" {
+					cancel()
+					return fmt.Errorf("failed to load signing keys: %w", err)
+				}
+				logger.Error("failed to load signing keys", "error", err)
+			}
+			go keys.refreshLoop(ctx)
+			return nil
+		},
+	})
+	coordinator.Register(shutdown.PhaseBackground, "auth key refresh", func(context.Context) error {
+		cancel()
+		return nil
+	})
+	return nil
+}
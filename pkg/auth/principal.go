@@ -0,0 +1,73 @@
+package auth
+
+import (
+	"context"
+	"slices"
+
+	apperrors "project_template/internal/shared/errors"
+)
+
+var (
+	ErrUnauthenticated = apperrors.New(apperrors.KindUnauthorized, "unauthenticated", "authentication required")
+	ErrInvalidToken    = apperrors.New(apperrors.KindUnauthorized, "invalid_token", "invalid bearer token")
+	ErrForbidden       = apperrors.New(apperrors.KindForbidden, "forbidden", "insufficient permissions")
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	// Subject identifies the caller, from the sub claim.
+	Subject string
+	Roles   []string
+	// Permissions are granted by Roles, as configured in Config.Roles.
+	Permissions []string
+	// Scopes come from the scope or scp claim.
+	Scopes []string
+	// Claims are all claims of the token.
+	Claims map[string]any
+}
+
+func (p *Principal) HasRole(role string) bool {
+	return slices.Contains(p.Roles, role)
+}
+
+// Can reports whether p holds permission.
+func (p *Principal) Can(permission string) bool {
+	return slices.ContainsFunc(p.Permissions, func(granted string) bool {
+		return grants(granted, permission)
+	})
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying p.
+func NewContext(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// FromContext returns the principal of the request ctx belongs to.
+func FromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// Require returns the principal of ctx or ErrUnauthenticated.
+func Require(ctx context.Context) (*Principal, error) {
+	p, ok := FromContext(ctx)
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return p, nil
+}
+
+// RequireRole returns the principal of ctx if it has role, and
+// ErrUnauthenticated or ErrForbidden otherwise.
+func RequireRole(ctx context.Context, role string) (*Principal, error) {
+	p, err := Require(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !p.HasRole(role) {
+		return nil, ErrForbidden
+	}
+	return p, nil
+}
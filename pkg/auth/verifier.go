@@ -0,0 +1,101 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Verifier validates bearer tokens and returns their principal.
+type Verifier struct {
+	config Config
+	keys   *KeySet
+	parser *jwt.Parser
+}
+
+func NewVerifier(config Config, keys *KeySet) *Verifier {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(config.Algorithms),
+		jwt.WithLeeway(config.Leeway),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+	}
+	if config.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		opts = append(opts, jwt.WithAudience(config.Audience))
+	}
+
+	return &Verifier{
+		config: config,
+		keys:   keys,
+		parser: jwt.NewParser(opts...),
+	}
+}
+
+// Verify checks the signature and claims of token. Errors describe why
+// the token was rejected and are meant for logs, not for clients.
+func (v *Verifier) Verify(ctx context.Context, token string) (*Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, errors.New("token has no subject")
+	}
+
+	return &Principal{
+		Subject: subject,
+		Roles:   stringsClaim(lookupClaim(claims, v.config.RolesClaim)),
+		Scopes:  scopes(claims),
+		Claims:  claims,
+	}, nil
+}
+
+// lookupClaim resolves a dotted path such as realm_access.roles.
+func lookupClaim(claims map[string]any, path string) any {
+	var value any = claims
+	for name := range strings.SplitSeq(path, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = m[name]
+	}
+	return value
+}
+
+// scopes reads the space-separated scope claim, or the scp claim used by
+// some issuers instead.
+func scopes(claims jwt.MapClaims) []string {
+	if s, ok := claims["scope"].(string); ok {
+		return strings.Fields(s)
+	}
+	return stringsClaim(claims["scp"])
+}
+
+func stringsClaim(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	default:
+		return nil
+	}
+}
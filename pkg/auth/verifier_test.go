@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"project_template/pkg/httpclient"
+
+	"github.com/creasty/defaults"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func testConfig(t *testing.T) Config {
+	t.Helper()
+	var cfg Config
+	if err := defaults.Set(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	cfg.Enabled = true
+	cfg.Issuer = "https://issuer.test/"
+	cfg.Audience = "project_template"
+	return cfg
+}
+
+func newTestKey(t *testing.T) *DevKey {
+	t.Helper()
+	key, err := LoadOrCreateDevKey(filepath.Join(t.TempDir(), "jwt.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func jwksOf(t *testing.T, key *DevKey) []byte {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	if err := key.WriteJWKS(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// jwksServer serves the JWKS returned by current and counts the requests.
+func jwksServer(t *testing.T, current func() []byte) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		_, _ = w.Write(current())
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &hits
+}
+
+func newTestKeySet(t *testing.T, cfg Config) *KeySet {
+	t.Helper()
+	var clientConfig httpclient.Config
+	if err := defaults.Set(&clientConfig); err != nil {
+		t.Fatal(err)
+	}
+	clients, err := httpclient.NewClients(clientConfig, discard, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewKeySet(cfg, clients, discard)
+}
+
+func validClaims(cfg Config) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"sub":   "alice",
+		"iss":   cfg.Issuer,
+		"aud":   cfg.Audience,
+		"roles": []string{"admin"},
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	key := newTestKey(t)
+	jwks := jwksOf(t, key)
+	srv, _ := jwksServer(t, func() []byte { return jwks })
+
+	cfg := testConfig(t)
+	cfg.JWKSURL = srv.URL
+	verifier := NewVerifier(cfg, newTestKeySet(t, cfg))
+
+	tests := []struct {
+		name   string
+		modify func(jwt.MapClaims)
+		ok     bool
+	}{
+		{name: "valid", modify: func(jwt.MapClaims) {}, ok: true},
+		{name: "expired", modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{name: "expired within leeway", modify: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-10 * time.Second).Unix() }, ok: true},
+		{name: "missing exp", modify: func(c jwt.MapClaims) { delete(c, "exp") }},
+		{name: "not yet valid", modify: func(c jwt.MapClaims) { c["nbf"] = time.Now().Add(time.Minute).Unix() }},
+		{name: "issuer mismatch", modify: func(c jwt.MapClaims) { c["iss"] = "https://other.test/" }},
+		{name: "audience mismatch", modify: func(c jwt.MapClaims) { c["aud"] = "other" }},
+		{name: "missing subject", modify: func(c jwt.MapClaims) { delete(c, "sub") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims(cfg)
+			tt.modify(claims)
+			token, err := key.Sign(claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			principal, err := verifier.Verify(context.Background(), token)
+			if !tt.ok {
+				if err == nil {
+					t.Fatal("token accepted, want rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("token rejected: %v", err)
+			}
+			if principal.Subject != "alice" || !principal.HasRole("admin") {
+				t.Errorf("principal = %+v, want subject alice with role admin", principal)
+			}
+		})
+	}
+}
+
+func TestVerifierRejectsDisallowedAlgorithms(t *testing.T) {
+	key := newTestKey(t)
+	jwks := jwksOf(t, key)
+	srv, _ := jwksServer(t, func() []byte { return jwks })
+
+	cfg := testConfig(t)
+	cfg.JWKSURL = srv.URL
+	keys := newTestKeySet(t, cfg)
+
+	es256, err := key.Sign(validClaims(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	none, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims(cfg)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaOnly := cfg
+	rsaOnly.Algorithms = []string{"RS256"}
+	if _, err := NewVerifier(rsaOnly, keys).Verify(context.Background(), es256); err == nil {
+		t.Error("ES256 token accepted with only RS256 allowed")
+	}
+	if _, err := NewVerifier(cfg, keys).Verify(context.Background(), none); err == nil {
+		t.Error("unsigned token accepted")
+	}
+}
+
+func TestVerifierPicksUpRotatedKeys(t *testing.T) {
+	oldKey, newKey := newTestKey(t), newTestKey(t)
+	var current atomic.Value
+	current.Store(jwksOf(t, oldKey))
+	srv, _ := jwksServer(t, func() []byte { return current.Load().([]byte) })
+
+	cfg := testConfig(t)
+	cfg.JWKSURL = srv.URL
+	cfg.MinRefreshInterval = 0
+	keys := newTestKeySet(t, cfg)
+	if err := keys.load(context.Background(), 0); err != nil {
+		t.Fatal(err)
+	}
+
+	current.Store(jwksOf(t, newKey))
+	token, err := newKey.Sign(validClaims(cfg))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewVerifier(cfg, keys).Verify(context.Background(), token); err != nil {
+		t.Fatalf("token signed with rotated key rejected: %v", err)
+	}
+}
+
+func TestGuard(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.Roles = map[string][]string{"reader": {"users:read"}}
+	guard := NewGuard(cfg, NewAuthorizer(cfg))
+
+	reader := &Principal{Subject: "alice", Roles: []string{"reader"}, Permissions: []string{"users:read"}}
+	tests := []struct {
+		name  string
+		route any
+		ctx   context.Context
+		want  error
+	}{
+		{name: "public route", route: publicRoute{}, ctx: context.Background()},
+		{name: "anonymous on protected route", route: protectedRoute{}, ctx: context.Background(), want: ErrUnauthenticated},
+		{name: "invalid token on protected route", route: protectedRoute{}, ctx: withTokenError(context.Background(), ErrInvalidToken), want: ErrInvalidToken},
+		{name: "invalid token on public route", route: publicRoute{}, ctx: withTokenError(context.Background(), ErrInvalidToken)},
+		{name: "principal on protected route", route: protectedRoute{}, ctx: NewContext(context.Background(), reader)},
+		{name: "anonymous missing permission", route: permissionRoute{"users:write"}, ctx: context.Background(), want: ErrUnauthenticated},
+		{name: "principal missing permission", route: permissionRoute{"users:write"}, ctx: NewContext(context.Background(), reader), want: ErrForbidden},
+		{name: "principal with permission", route: permissionRoute{"users:read"}, ctx: NewContext(context.Background(), reader)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequestWithContext(tt.ctx, http.MethodGet, "/", nil)
+
+			err := guard.Check(w, r, tt.route)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Check() = %v, want %v", err, tt.want)
+			}
+			challenged := w.Header().Get("WWW-Authenticate") != ""
+			if unauthorized := errors.Is(err, ErrUnauthenticated) || errors.Is(err, ErrInvalidToken); challenged != unauthorized {
+				t.Errorf("WWW-Authenticate = %q for error %v", w.Header().Get("WWW-Authenticate"), err)
+			}
+		})
+	}
+}
+
+type publicRoute struct{}
+
+func (publicRoute) RequiresAuth() bool { return false }
+
+type protectedRoute struct{}
+
+func (protectedRoute) RequiresAuth() bool { return true }
+
+type permissionRoute []string
+
+func (p permissionRoute) RequiredPermissions() []string { return slices.Clone(p) }
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Typed errors for PostgreSQL failures that callers are expected to handle.
+// Use errors.Is to test for them and errors.As with *ConstraintError to
+// get the violated constraint.
+var (
+	ErrUniqueViolation      = errors.New("unique constraint violation")
+	ErrForeignKeyViolation  = errors.New("foreign key constraint violation")
+	ErrCheckViolation       = errors.New("check constraint violation")
+	ErrNotNullViolation     = errors.New("not-null constraint violation")
+	ErrSerializationFailure = errors.New("serialization failure")
+)
+
+// PostgreSQL SQLSTATE codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgCheckViolation       = "23514"
+	pgNotNullViolation     = "23502"
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+)
+
+// ConstraintError is returned by TranslateError for PostgreSQL errors that
+// map to one of the typed errors above. It matches both the typed error and
+// the original *pgconn.PgError.
+type ConstraintError struct {
+	Kind       error
+	Table      string
+	Column     string
+	Constraint string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	if e.Constraint != "" {
+		return e.Kind.Error() + " (" + e.Constraint + ")"
+	}
+	return e.Kind.Error()
+}
+
+func (e *ConstraintError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// TranslateError maps PostgreSQL errors to the typed errors of this package.
+// Errors it does not recognize are returned unchanged.
+func TranslateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	var kind error
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		kind = ErrUniqueViolation
+	case pgForeignKeyViolation:
+		kind = ErrForeignKeyViolation
+	case pgCheckViolation:
+		kind = ErrCheckViolation
+	case pgNotNullViolation:
+		kind = ErrNotNullViolation
+	case pgSerializationFailure, pgDeadlockDetected:
+		// Both are transient and resolved by retrying the transaction.
+		kind = ErrSerializationFailure
+	default:
+		return err
+	}
+
+	return &ConstraintError{
+		Kind:       kind,
+		Table:      pgErr.TableName,
+		Column:     pgErr.ColumnName,
+		Constraint: pgErr.ConstraintName,
+		Err:        err,
+	}
+}
@@ -0,0 +1,21 @@
+package database
+
+import (
+	"context"
+
+	"project_template/pkg/health"
+
+	"gorm.io/gorm"
+)
+
+// NewHealthChecker reports the instance as not ready while the database
+// cannot be reached.
+func NewHealthChecker(db *gorm.DB) health.Checker {
+	return health.NewCheckerFunc("database", health.Readiness, func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
@@ -1,9 +1,16 @@
 package database
 
-import "go.uber.org/fx"
+import (
+	"project_template/pkg/health"
+	"project_template/pkg/logger"
+
+	"go.uber.org/fx"
+)
 
 var Module = fx.Module("database",
+	logger.ForModule("database"),
 	fx.Provide(
 		NewConnection,
+		health.AsChecker(NewHealthChecker),
 	),
 )
@@ -0,0 +1,226 @@
+package database
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json/v2"
+	"errors"
+	"slices"
+	"strings"
+
+	"project_template/pkg/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSort   = errors.New("invalid sort")
+)
+
+// SortField describes a column that clients may sort and paginate by.
+type SortField[T any] struct {
+	// Column is the database column name.
+	Column string
+	// Value extracts the cursor representation of the column from an entity.
+	Value func(*T) string
+	// Parse converts a cursor value back into a query argument.
+	// If nil, the string value is used as is.
+	Parse func(string) (any, error)
+}
+
+// Keyset describes how entities of type T are paginated by key: the
+// whitelist of sortable fields and the unique column used to break ties.
+type Keyset[T any] struct {
+	IDColumn string
+	ID       func(*T) string
+	Fields   map[string]SortField[T]
+}
+
+// PageQuery requests a page of entities matching Where.
+// Sort is a field name from the keyset, prefixed with "-" for descending
+// order. Cursor is an opaque value previously returned in a Page.
+// Filter identifies the conditions in Where, such as a hash of the
+// request filters; it is bound into cursors so that a cursor is rejected
+// by a query with other filters. Limit must be positive.
+type PageQuery[T any] struct {
+	Where  []Specification[T]
+	Sort   string
+	Filter string
+	Cursor string
+	Limit  int
+}
+
+// Page is a single page of results with opaque cursors to the
+// neighbouring pages. A cursor is empty if there is no such page.
+// Pages fetched by offset carry NextOffset and PrevOffset instead,
+// which are nil if there is no such page.
+type Page[T any] struct {
+	Items      []*T
+	NextCursor string
+	PrevCursor string
+	NextOffset *int
+	PrevOffset *int
+}
+
+// cursor is the decoded form of an opaque page cursor.
+type cursor struct {
+	Sort     string `json:"s"`
+	Filter   string `json:"f,omitempty"`
+	Value    string `json:"v"`
+	ID       string `json:"id"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+func (c cursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(s string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// FindPage returns a page of entities using keyset pagination: rows are
+// ordered by the requested sort field with ties broken by the ID column,
+// and the cursor marks the row the page continues from. Unlike offset
+// pagination, the cost of a page does not grow with its position.
+func (r *Repository[T, ID]) FindPage(ctx context.Context, ks Keyset[T], q PageQuery[T]) (Page[T], error) {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "FindPage")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("query.sort", q.Sort),
+		attribute.Int("query.limit", q.Limit),
+		attribute.Bool("query.cursor", q.Cursor != ""),
+	)
+
+	sortName := strings.TrimPrefix(q.Sort, "-")
+	desc := strings.HasPrefix(q.Sort, "-")
+	field, ok := ks.Fields[sortName]
+	if !ok {
+		return Page[T]{}, ErrInvalidSort
+	}
+
+	var after *cursor
+	if q.Cursor != "" {
+		c, err := decodeCursor(q.Cursor)
+		if err != nil || c.Sort != q.Sort || c.Filter != q.Filter {
+			return Page[T]{}, ErrInvalidCursor
+		}
+		after = &c
+	}
+
+	backward := after != nil && after.Backward
+	// Walking backwards reverses the scan order; results are flipped
+	// back before returning so the page is always in the requested order.
+	scanDesc := desc != backward
+
+	db := And(q.Where...)(r.db.WithContext(ctx))
+	if after != nil {
+		value := any(after.Value)
+		if field.Parse != nil {
+			v, err := field.Parse(after.Value)
+			if err != nil {
+				return Page[T]{}, ErrInvalidCursor
+			}
+			value = v
+		}
+		op := ">"
+		if scanDesc {
+			op = "<"
+		}
+		db = db.Where(clause.Expr{
+			SQL:  "(?, ?) " + op + " (?, ?)",
+			Vars: []any{clause.Column{Name: field.Column}, clause.Column{Name: ks.IDColumn}, value, after.ID},
+		})
+	}
+	db = db.
+		Order(clause.OrderByColumn{Column: clause.Column{Name: field.Column}, Desc: scanDesc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: ks.IDColumn}, Desc: scanDesc})
+
+	// Fetch one extra row to learn whether another page exists.
+	var items []*T
+	if err := db.Limit(q.Limit + 1).Find(&items).Error; err != nil {
+		telemetry.RecordError(span, err)
+		return Page[T]{}, err
+	}
+	hasMore := len(items) > q.Limit
+	if hasMore {
+		items = items[:q.Limit]
+	}
+	if backward {
+		slices.Reverse(items)
+	}
+	span.SetAttributes(attribute.Int("result.count", len(items)))
+
+	page := Page[T]{Items: items}
+	if len(items) == 0 {
+		return page, nil
+	}
+	first, last := items[0], items[len(items)-1]
+	if hasMore || backward {
+		page.NextCursor = cursor{Sort: q.Sort, Filter: q.Filter, Value: field.Value(last), ID: ks.ID(last)}.encode()
+	}
+	if after != nil && (!backward || hasMore) {
+		page.PrevCursor = cursor{Sort: q.Sort, Filter: q.Filter, Value: field.Value(first), ID: ks.ID(first), Backward: true}.encode()
+	}
+	return page, nil
+}
+
+// FindPageAt returns the page of entities starting at offset, in the
+// order of q.Sort with ties broken by the ID column. q.Cursor is ignored.
+// Offset pages let clients jump to any page, but their cost grows with
+// the offset and rows inserted or deleted meanwhile shift them.
+func (r *Repository[T, ID]) FindPageAt(ctx context.Context, ks Keyset[T], q PageQuery[T], offset int) (Page[T], error) {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "FindPageAt")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("query.sort", q.Sort),
+		attribute.Int("query.limit", q.Limit),
+		attribute.Int("query.offset", offset),
+	)
+
+	field, ok := ks.Fields[strings.TrimPrefix(q.Sort, "-")]
+	if !ok {
+		return Page[T]{}, ErrInvalidSort
+	}
+	desc := strings.HasPrefix(q.Sort, "-")
+
+	// Fetch one extra row to learn whether another page exists.
+	var items []*T
+	err := And(q.Where...)(r.db.WithContext(ctx)).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: field.Column}, Desc: desc}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: ks.IDColumn}, Desc: desc}).
+		Offset(offset).
+		Limit(q.Limit + 1).
+		Find(&items).Error
+	if err != nil {
+		telemetry.RecordError(span, err)
+		return Page[T]{}, err
+	}
+	hasMore := len(items) > q.Limit
+	if hasMore {
+		items = items[:q.Limit]
+	}
+	span.SetAttributes(attribute.Int("result.count", len(items)))
+
+	page := Page[T]{Items: items}
+	if hasMore {
+		next := offset + q.Limit
+		page.NextOffset = &next
+	}
+	if offset > 0 {
+		prev := max(offset-q.Limit, 0)
+		page.PrevOffset = &prev
+	}
+	return page, nil
+}
@@ -0,0 +1,220 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"project_template/pkg/telemetry"
+
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNotFound = errors.New("record not found")
+)
+
+// Repository provides CRUD operations for entities of type T identified
+// by a primary key of type ID. Every operation is traced with
+// telemetry.StartRepositorySpan using the repository name given at
+// construction, and failures other than ErrNotFound are recorded on the span.
+// Operations on a single entity record its primary key as db.entity.id.
+// Write errors are passed through TranslateError.
+//
+// Soft deletes are handled by GORM: if T has a gorm.DeletedAt field,
+// Delete marks the row as deleted and reads exclude it unless the query
+// scope says otherwise.
+type Repository[T any, ID comparable] struct {
+	db   *gorm.DB
+	name string
+}
+
+// NewRepository creates a repository for T. The name is used as the
+// tracer and repository.name span attribute, e.g. "UserRepository".
+func NewRepository[T any, ID comparable](db *gorm.DB, name string) *Repository[T, ID] {
+	return &Repository[T, ID]{
+		db:   db,
+		name: name,
+	}
+}
+
+// DB returns a session bound to ctx for queries the repository does not cover.
+func (r *Repository[T, ID]) DB(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx)
+}
+
+func (r *Repository[T, ID]) Create(ctx context.Context, entity *T) error {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "Create")
+	defer span.End()
+
+	err := TranslateError(r.db.WithContext(ctx).Create(entity).Error)
+	if err != nil {
+		telemetry.RecordError(span, err)
+	} else if id, ok := r.primaryKey(ctx, entity); ok {
+		span.SetAttributes(entityID(id))
+	}
+	return err
+}
+
+func (r *Repository[T, ID]) GetByID(ctx context.Context, id ID) (*T, error) {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "GetByID")
+	defer span.End()
+	span.SetAttributes(entityID(id))
+
+	var entity T
+	err := r.db.WithContext(ctx).Where(byPrimaryKey(id)).Take(&entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		telemetry.RecordError(span, err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// FindOne returns the first entity matching q, or ErrNotFound.
+func (r *Repository[T, ID]) FindOne(ctx context.Context, q Query[T]) (*T, error) {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "FindOne")
+	defer span.End()
+
+	var entity T
+	err := q.applyPaging(q.apply(r.db.WithContext(ctx))).Take(&entity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+		telemetry.RecordError(span, err)
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// Find returns all entities matching q, sorted and paginated as requested.
+func (r *Repository[T, ID]) Find(ctx context.Context, q Query[T]) ([]*T, error) {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "Find")
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int("query.limit", q.Limit),
+		attribute.Int("query.offset", q.Offset),
+	)
+
+	var entities []*T
+	err := q.applyPaging(q.apply(r.db.WithContext(ctx))).Find(&entities).Error
+	if err != nil {
+		telemetry.RecordError(span, err)
+	}
+	span.SetAttributes(attribute.Int("result.count", len(entities)))
+	return entities, err
+}
+
+// Count returns the number of entities matching the filters of q.
+// Sorting and pagination are ignored.
+func (r *Repository[T, ID]) Count(ctx context.Context, q Query[T]) (int64, error) {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "Count")
+	defer span.End()
+
+	var count int64
+	err := q.apply(r.db.WithContext(ctx).Model(new(T))).Count(&count).Error
+	if err != nil {
+		telemetry.RecordError(span, err)
+	}
+	return count, err
+}
+
+func (r *Repository[T, ID]) Update(ctx context.Context, entity *T) error {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "Update")
+	defer span.End()
+	if id, ok := r.primaryKey(ctx, entity); ok {
+		span.SetAttributes(entityID(id))
+	}
+
+	err := TranslateError(r.db.WithContext(ctx).Save(entity).Error)
+	if err != nil {
+		telemetry.RecordError(span, err)
+	}
+	return err
+}
+
+// Delete removes the entity with the given id. For models with a
+// gorm.DeletedAt field this is a soft delete. ErrNotFound is returned
+// if no live row matched.
+func (r *Repository[T, ID]) Delete(ctx context.Context, id ID) error {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "Delete")
+	defer span.End()
+	span.SetAttributes(entityID(id))
+
+	result := r.db.WithContext(ctx).Where(byPrimaryKey(id)).Delete(new(T))
+	if result.Error != nil {
+		err := TranslateError(result.Error)
+		telemetry.RecordError(span, err)
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Restore undoes a soft delete. ErrNotFound is returned if there is no
+// soft-deleted row with the given id.
+func (r *Repository[T, ID]) Restore(ctx context.Context, id ID) error {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "Restore")
+	defer span.End()
+	span.SetAttributes(entityID(id))
+
+	result := Deleted[T]()(r.db.WithContext(ctx).Model(new(T))).
+		Where(byPrimaryKey(id)).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		err := TranslateError(result.Error)
+		telemetry.RecordError(span, err)
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// HardDelete permanently removes the entity with the given id, whether
+// or not it was soft-deleted. ErrNotFound is returned if no row matched.
+func (r *Repository[T, ID]) HardDelete(ctx context.Context, id ID) error {
+	ctx, span := telemetry.StartRepositorySpan(ctx, r.name, "HardDelete")
+	defer span.End()
+	span.SetAttributes(entityID(id))
+
+	result := r.db.WithContext(ctx).Unscoped().Where(byPrimaryKey(id)).Delete(new(T))
+	if result.Error != nil {
+		err := TranslateError(result.Error)
+		telemetry.RecordError(span, err)
+		return err
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// entityID is the span attribute naming the entity an operation targets.
+func entityID(id any) attribute.KeyValue {
+	return attribute.String("db.entity.id", fmt.Sprint(id))
+}
+
+// primaryKey returns the primary key of entity, if it has a single one
+// that is set.
+func (r *Repository[T, ID]) primaryKey(ctx context.Context, entity *T) (any, bool) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(entity); err != nil || stmt.Schema.PrioritizedPrimaryField == nil {
+		return nil, false
+	}
+	id, zero := stmt.Schema.PrioritizedPrimaryField.ValueOf(ctx, reflect.ValueOf(entity))
+	return id, !zero
+}
+
+func byPrimaryKey(id any) clause.Expression {
+	return clause.Eq{Column: clause.PrimaryColumn, Value: id}
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Specification is a reusable query predicate for entities of type T.
+// The type parameter ties a specification to a single entity so that
+// a filter written for one repository cannot be passed to another.
+type Specification[T any] func(db *gorm.DB) *gorm.DB
+
+// Where builds a specification from a raw condition and its arguments.
+func Where[T any](query any, args ...any) Specification[T] {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	}
+}
+
+// Eq matches rows where column equals value.
+func Eq[T any](column string, value any) Specification[T] {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.Eq{Column: clause.Column{Name: column}, Value: value})
+	}
+}
+
+// In matches rows where column is one of values.
+func In[T any](column string, values ...any) Specification[T] {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(clause.IN{Column: clause.Column{Name: column}, Values: values})
+	}
+}
+
+// Deleted matches soft-deleted rows only.
+func Deleted[T any]() Specification[T] {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+}
+
+// And combines specifications so that all of them must match.
+func And[T any](specs ...Specification[T]) Specification[T] {
+	return func(db *gorm.DB) *gorm.DB {
+		for _, spec := range specs {
+			if spec != nil {
+				db = spec(db)
+			}
+		}
+		return db
+	}
+}
+
+// Or combines specifications so that at least one of them must match.
+func Or[T any](specs ...Specification[T]) Specification[T] {
+	return func(db *gorm.DB) *gorm.DB {
+		var group *gorm.DB
+		for _, spec := range specs {
+			if spec == nil {
+				continue
+			}
+			cond := spec(db.Session(&gorm.Session{NewDB: true}))
+			if group == nil {
+				group = cond
+			} else {
+				group = group.Or(cond)
+			}
+		}
+		if group == nil {
+			return db
+		}
+		return db.Where(group)
+	}
+}
+
+// Sort describes an ORDER BY column.
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+// Asc returns an ascending sort on column.
+func Asc(column string) Sort {
+	return Sort{Column: column}
+}
+
+// Desc returns a descending sort on column.
+func Desc(column string) Sort {
+	return Sort{Column: column, Desc: true}
+}
+
+// SoftDeleteScope controls how soft-deleted rows are treated by a query.
+type SoftDeleteScope int
+
+const (
+	// ExcludeDeleted hides soft-deleted rows (the GORM default).
+	ExcludeDeleted SoftDeleteScope = iota
+	// IncludeDeleted returns both live and soft-deleted rows.
+	IncludeDeleted
+	// OnlyDeleted returns soft-deleted rows only.
+	OnlyDeleted
+)
+
+// Query describes filtering, sorting and pagination for Repository.Find.
+type Query[T any] struct {
+	Where  []Specification[T]
+	Sort   []Sort
+	Limit  int
+	Offset int
+	Scope  SoftDeleteScope
+}
+
+// apply applies the filtering and soft-delete scope of q to db.
+// Sorting and pagination are applied separately so that the same
+// filters can be reused for counting.
+func (q Query[T]) apply(db *gorm.DB) *gorm.DB {
+	switch q.Scope {
+	case IncludeDeleted:
+		db = db.Unscoped()
+	case OnlyDeleted:
+		db = Deleted[T]()(db)
+	}
+	return And(q.Where...)(db)
+}
+
+func (q Query[T]) applyPaging(db *gorm.DB) *gorm.DB {
+	for _, s := range q.Sort {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Column}, Desc: s.Desc})
+	}
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+	if q.Offset > 0 {
+		db = db.Offset(q.Offset)
+	}
+	return db
+}
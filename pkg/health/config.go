@@ -0,0 +1,11 @@
+package health
+
+import "time"
+
+type Config struct {
+	// Timeout bounds each check unless the checker sets its own.
+	Timeout time.Duration `mapstructure:"timeout" default:"2s"`
+	// CacheTTL is how long a check result is reused before the check runs
+	// again, so that frequent probes do not hammer dependencies.
+	CacheTTL time.Duration `mapstructure:"cache_ttl" default:"2s"`
+}
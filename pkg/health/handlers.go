@@ -0,0 +1,73 @@
+package health
+
+import (
+	"encoding/json/v2"
+	"net/http"
+
+	"project_template/pkg/webserver"
+)
+
+// LivenessHandler handles GET /healthz
+type LivenessHandler struct {
+	health *Health
+}
+
+func NewLivenessHandler(health *Health) *LivenessHandler {
+	return &LivenessHandler{health: health}
+}
+
+func (*LivenessHandler) Pattern() string {
+	return "GET /healthz"
+}
+
+// RequiresAuth is false so that probes pass when authentication is
+// required by default.
+func (*LivenessHandler) RequiresAuth() bool {
+	return false
+}
+
+// RateLimit is disabled: probes from the kubelet must never be throttled,
+// or the instance is restarted or taken out of service.
+func (*LivenessHandler) RateLimit() webserver.RateLimit {
+	return webserver.RateLimit{Disabled: true}
+}
+
+func (h *LivenessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, h.health.Liveness(r.Context()))
+}
+
+// ReadinessHandler handles GET /readyz
+type ReadinessHandler struct {
+	health *Health
+}
+
+func NewReadinessHandler(health *Health) *ReadinessHandler {
+	return &ReadinessHandler{health: health}
+}
+
+func (*ReadinessHandler) Pattern() string {
+	return "GET /readyz"
+}
+
+func (*ReadinessHandler) RequiresAuth() bool {
+	return false
+}
+
+func (*ReadinessHandler) RateLimit() webserver.RateLimit {
+	return webserver.RateLimit{Disabled: true}
+}
+
+func (h *ReadinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	writeReport(w, h.health.Readiness(r.Context()))
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if report.Healthy() {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.MarshalWrite(w, report)
+}
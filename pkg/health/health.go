@@ -0,0 +1,153 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// CheckResult is the outcome of a single check.
+type CheckResult struct {
+	Status    string    `json:"status"`
+	Kind      string    `json:"kind"`
+	Error     string    `json:"error,omitempty"`
+	Duration  string    `json:"duration"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report aggregates the results of the checks relevant to a probe.
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+// Healthy reports whether the probe passed.
+func (r Report) Healthy() bool {
+	return r.Status == StatusUp
+}
+
+// Health runs the registered checkers and caches their results.
+type Health struct {
+	config   Config
+	checkers []Checker
+
+	mu    sync.Mutex
+	cache map[string]CheckResult
+	// refresh merges concurrent cache misses of a check into one run.
+	refresh singleflight.Group
+
+	draining atomic.Bool
+}
+
+func NewHealth(config Config, params CheckerParams) *Health {
+	return &Health{
+		config:   config,
+		checkers: params.Checkers,
+		cache:    make(map[string]CheckResult),
+	}
+}
+
+// Liveness runs the liveness checks.
+func (h *Health) Liveness(ctx context.Context) Report {
+	return h.run(ctx, func(k Kind) bool { return k == Liveness })
+}
+
+// Readiness runs the readiness and liveness checks. Informational checks
+// are included in the report without affecting its status. Once draining
+// has started readiness always fails.
+func (h *Health) Readiness(ctx context.Context) Report {
+	report := h.run(ctx, func(Kind) bool { return true })
+	if h.draining.Load() {
+		report.Status = StatusDown
+		report.Checks["shutdown"] = CheckResult{
+			Status:    StatusDown,
+			Kind:      Readiness.String(),
+			Error:     "shutting down",
+			CheckedAt: time.Now(),
+		}
+	}
+	return report
+}
+
+// StartDraining makes readiness fail for the rest of the process lifetime.
+func (h *Health) StartDraining() {
+	h.draining.Store(true)
+}
+
+func (h *Health) run(ctx context.Context, include func(Kind) bool) Report {
+	report := Report{Status: StatusUp, Checks: make(map[string]CheckResult)}
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+	for _, checker := range h.checkers {
+		if !include(checker.Kind()) {
+			continue
+		}
+		wg.Go(func() {
+			result := h.check(ctx, checker)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[checker.Name()] = result
+			if result.Status != StatusUp && checker.Kind() != Informational {
+				report.Status = StatusDown
+			}
+		})
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (h *Health) check(ctx context.Context, checker Checker) CheckResult {
+	h.mu.Lock()
+	cached, ok := h.cache[checker.Name()]
+	h.mu.Unlock()
+	if ok && time.Since(cached.CheckedAt) < h.config.CacheTTL {
+		return cached
+	}
+
+	result, _, _ := h.refresh.Do(checker.Name(), func() (any, error) {
+		return h.runCheck(ctx, checker), nil
+	})
+	return result.(CheckResult)
+}
+
+// runCheck runs checker and caches its result. The check is not canceled
+// with ctx, as concurrent callers may be waiting for its result.
+func (h *Health) runCheck(ctx context.Context, checker Checker) CheckResult {
+	timeout := h.config.Timeout
+	if tc, ok := checker.(TimeoutChecker); ok {
+		timeout = tc.Timeout()
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := checker.Check(ctx)
+	result := CheckResult{
+		Status:    StatusUp,
+		Kind:      checker.Kind().String(),
+		Duration:  time.Since(start).String(),
+		CheckedAt: start,
+	}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+
+	h.mu.Lock()
+	h.cache[checker.Name()] = result
+	h.mu.Unlock()
+
+	return result
+}
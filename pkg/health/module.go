@@ -0,0 +1,42 @@
+package health
+
+import (
+	"context"
+
+	"project_template/pkg/shutdown"
+	"project_template/pkg/webserver"
+
+	"go.uber.org/fx"
+)
+
+// CheckerParams collects all registered checkers via fx dependency injection.
+type CheckerParams struct {
+	fx.In
+	Checkers []Checker `group:"health_checkers"`
+}
+
+// AsChecker annotates a checker constructor to be collected by the health module.
+func AsChecker(f any) any {
+	return fx.Annotate(
+		f,
+		fx.As(new(Checker)),
+		fx.ResultTags(`group:"health_checkers"`),
+	)
+}
+
+var Module = fx.Module("health",
+	fx.Provide(
+		NewHealth,
+		webserver.AsRoute(NewLivenessHandler),
+		webserver.AsRoute(NewReadinessHandler),
+	),
+	fx.Invoke(registerShutdown),
+)
+
+// registerShutdown fails readiness as the first step of shutdown.
+func registerShutdown(h *Health, coordinator *shutdown.Coordinator) {
+	coordinator.Register(shutdown.PhaseDrain, "health", func(context.Context) error {
+		h.StartDraining()
+		return nil
+	})
+}
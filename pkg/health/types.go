@@ -0,0 +1,75 @@
+package health
+
+import (
+	"context"
+	"time"
+)
+
+// Kind tells how the result of a check affects the reported status.
+type Kind int
+
+const (
+	// Readiness checks take the instance out of rotation when failing.
+	Readiness Kind = iota
+	// Liveness checks signal that the process must be restarted when
+	// failing. They are also part of readiness.
+	Liveness
+	// Informational checks are reported but never affect the status.
+	Informational
+)
+
+func (k Kind) String() string {
+	switch k {
+	case Liveness:
+		return "liveness"
+	case Informational:
+		return "informational"
+	default:
+		return "readiness"
+	}
+}
+
+// Checker reports the health of a single dependency.
+type Checker interface {
+	// Name identifies the check in reports, e.g. "database".
+	Name() string
+	// Kind tells whether the check gates liveness, readiness or neither.
+	Kind() Kind
+	// Check returns nil if the dependency is healthy.
+	Check(ctx context.Context) error
+}
+
+// TimeoutChecker is implemented by checkers that need a timeout other
+// than Config.Timeout.
+type TimeoutChecker interface {
+	Checker
+	Timeout() time.Duration
+}
+
+// CheckerFunc is a function type that implements Checker.
+type CheckerFunc struct {
+	name    string
+	kind    Kind
+	checkFn func(ctx context.Context) error
+}
+
+// NewCheckerFunc creates a new CheckerFunc.
+func NewCheckerFunc(name string, kind Kind, fn func(ctx context.Context) error) *CheckerFunc {
+	return &CheckerFunc{
+		name:    name,
+		kind:    kind,
+		checkFn: fn,
+	}
+}
+
+func (c *CheckerFunc) Name() string {
+	return c.name
+}
+
+func (c *CheckerFunc) Kind() Kind {
+	return c.kind
+}
+
+func (c *CheckerFunc) Check(ctx context.Context) error {
+	return c.checkFn(ctx)
+}
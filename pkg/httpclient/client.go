@@ -0,0 +1,143 @@
+package httpclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"project_template/pkg/telemetry"
+)
+
+// Clients hands out one Client per upstream. Bounded contexts inject it
+// and keep the client of each service they integrate with:
+//
+//	payments := clients.Get("payments")
+//	req, err := payments.NewRequest(ctx, http.MethodGet, "/charges/"+id, nil)
+//	resp, err := payments.Do(req)
+type Clients struct {
+	config  Config
+	logger  *slog.Logger
+	metrics *metrics
+	// defaultBaseURL is the base URL of upstreams missing from the
+	// configuration.
+	defaultBaseURL *url.URL
+
+	mu      sync.Mutex
+	clients map[string]*Client
+}
+
+// NewClients creates the clients of all configured upstreams, so that an
+// invalid base URL fails startup rather than Get. It depends on telemetry
+// so that the global tracer and meter providers are set before the
+// clients are instrumented.
+func NewClients(config Config, logger *slog.Logger, _ *telemetry.Telemetry) (*Clients, error) {
+	m, err := newMetrics()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client metrics: %w", err)
+	}
+
+	defaultBaseURL, err := parseBaseURL(config.Defaults.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid default base URL: %w", err)
+	}
+
+	c := &Clients{
+		config:         config,
+		logger:         logger,
+		metrics:        m,
+		defaultBaseURL: defaultBaseURL,
+		clients:        make(map[string]*Client),
+	}
+	for name := range config.Upstreams {
+		cfg, _ := config.upstream(name)
+		baseURL, err := parseBaseURL(cfg.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base URL for upstream %q: %w", name, err)
+		}
+		c.clients[name] = c.newClient(name, cfg, baseURL)
+	}
+	return c, nil
+}
+
+// Get returns the client of the named upstream. An upstream missing from
+// the configuration gets a client with the default settings.
+func (c *Clients) Get(name string) *Client {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if client, ok := c.clients[name]; ok {
+		return client
+	}
+
+	c.logger.Debug("HTTP upstream is not configured, using defaults", "upstream", name)
+	cfg, _ := c.config.upstream(name)
+	client := c.newClient(name, cfg, c.defaultBaseURL)
+	c.clients[name] = client
+	return client
+}
+
+func (c *Clients) newClient(name string, cfg UpstreamConfig, baseURL *url.URL) *Client {
+	base := http.DefaultTransport.(*http.Transport).Clone()
+	base.ResponseHeaderTimeout = cfg.AttemptTimeout
+
+	return &Client{
+		Client: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: newTransport(name, cfg, base, c.metrics, c.logger),
+		},
+		name:    name,
+		baseURL: baseURL,
+	}
+}
+
+// parseBaseURL parses a configured base URL, which may be empty.
+func parseBaseURL(s string) (*url.URL, error) {
+	if s == "" {
+		return nil, nil
+	}
+	return url.Parse(s)
+}
+
+// closeIdleConnections releases pooled connections of all clients.
+func (c *Clients) closeIdleConnections() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, client := range c.clients {
+		client.CloseIdleConnections()
+	}
+}
+
+// Client is an http.Client for one upstream. Requests are traced, measured,
+// retried and guarded by a circuit breaker per host.
+type Client struct {
+	*http.Client
+	name    string
+	baseURL *url.URL
+}
+
+// Name returns the upstream name.
+func (c *Client) Name() string {
+	return c.name
+}
+
+// NewRequest creates a request for path, resolved against the upstream's
+// base URL if one is configured.
+func (c *Client) NewRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	target := path
+	if c.baseURL != nil {
+		ref, err := url.Parse(strings.TrimPrefix(path, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid request path: %w", err)
+		}
+		base := *c.baseURL
+		if !strings.HasSuffix(base.Path, "/") {
+			base.Path += "/"
+		}
+		target = base.ResolveReference(ref).String()
+	}
+	return http.NewRequestWithContext(ctx, method, target, body)
+}
@@ -0,0 +1,95 @@
+package httpclient
+
+import (
+	"maps"
+	"time"
+)
+
+type Config struct {
+	// Defaults apply to every upstream and fill in the settings an
+	// upstream leaves unset.
+	Defaults UpstreamConfig `mapstructure:"defaults"`
+	// Upstreams are the named services the application calls, e.g.
+	// "payments", as passed to Clients.Get.
+	Upstreams map[string]UpstreamConfig `mapstructure:"upstreams"`
+}
+
+type UpstreamConfig struct {
+	// BaseURL is what Client.NewRequest resolves paths against.
+	BaseURL string `mapstructure:"base_url"`
+	// Timeout bounds a whole call, including retries and reading the
+	// response body.
+	Timeout time.Duration `mapstructure:"timeout" default:"30s"`
+	// AttemptTimeout bounds the wait for the response headers of a single
+	// attempt.
+	AttemptTimeout time.Duration `mapstructure:"attempt_timeout" default:"10s"`
+	// Headers are added to every request that does not set them itself.
+	Headers map[string]string `mapstructure:"headers"`
+
+	Retry          RetryConfig   `mapstructure:"retry"`
+	CircuitBreaker BreakerConfig `mapstructure:"circuit_breaker"`
+}
+
+// RetryConfig applies to idempotent requests that fail with a network
+// error or a 429, 502, 503 or 504 response.
+type RetryConfig struct {
+	// MaxAttempts includes the first attempt; 1 disables retries.
+	MaxAttempts    int           `mapstructure:"max_attempts" default:"3"`
+	InitialBackoff time.Duration `mapstructure:"initial_backoff" default:"100ms"`
+	// MaxBackoff caps the delay between attempts. A Retry-After longer
+	// than this is not waited for.
+	MaxBackoff time.Duration `mapstructure:"max_backoff" default:"2s"`
+}
+
+// BreakerConfig configures the circuit breaker kept per upstream host.
+type BreakerConfig struct {
+	Disabled bool `mapstructure:"disabled"`
+	// Failures is the number of consecutive network errors or 5xx
+	// responses that opens the circuit.
+	Failures uint32 `mapstructure:"failures" default:"5"`
+	// OpenTimeout is how long requests fail fast before a trial request
+	// is let through.
+	OpenTimeout time.Duration `mapstructure:"open_timeout" default:"30s"`
+}
+
+// upstream returns the settings of the named upstream, with unset fields
+// taken from Defaults.
+func (c Config) upstream(name string) (UpstreamConfig, bool) {
+	u, ok := c.Upstreams[name]
+	d := c.Defaults
+
+	if u.BaseURL == "" {
+		u.BaseURL = d.BaseURL
+	}
+	if u.Timeout == 0 {
+		u.Timeout = d.Timeout
+	}
+	if u.AttemptTimeout == 0 {
+		u.AttemptTimeout = d.AttemptTimeout
+	}
+	headers := maps.Clone(d.Headers)
+	if headers == nil {
+		headers = make(map[string]string)
+	}
+	maps.Copy(headers, u.Headers)
+	u.Headers = headers
+
+	if u.Retry.MaxAttempts == 0 {
+		u.Retry.MaxAttempts = d.Retry.MaxAttempts
+	}
+	if u.Retry.InitialBackoff == 0 {
+		u.Retry.InitialBackoff = d.Retry.InitialBackoff
+	}
+	if u.Retry.MaxBackoff == 0 {
+		u.Retry.MaxBackoff = d.Retry.MaxBackoff
+	}
+
+	u.CircuitBreaker.Disabled = u.CircuitBreaker.Disabled || d.CircuitBreaker.Disabled
+	if u.CircuitBreaker.Failures == 0 {
+		u.CircuitBreaker.Failures = d.CircuitBreaker.Failures
+	}
+	if u.CircuitBreaker.OpenTimeout == 0 {
+		u.CircuitBreaker.OpenTimeout = d.CircuitBreaker.OpenTimeout
+	}
+	return u, ok
+}
@@ -0,0 +1,61 @@
+package httpclient
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// metrics holds the HTTP client instruments shared by all upstreams.
+type metrics struct {
+	requestCounter  metric.Int64Counter
+	requestDuration metric.Float64Histogram
+	retries         metric.Int64Counter
+	breakerChanges  metric.Int64Counter
+}
+
+func newMetrics() (*metrics, error) {
+	meter := otel.Meter("http-client")
+
+	requestCounter, err := meter.Int64Counter(
+		"http.client.request.total",
+		metric.WithDescription("Total number of outgoing HTTP requests"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	requestDuration, err := meter.Float64Histogram(
+		"http.client.request.duration",
+		metric.WithDescription("Outgoing HTTP request duration in seconds, including retries"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	retries, err := meter.Int64Counter(
+		"http.client.retries",
+		metric.WithDescription("Total number of retried outgoing HTTP requests"),
+		metric.WithUnit("{request}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	breakerChanges, err := meter.Int64Counter(
+		"http.client.circuit_breaker.transitions",
+		metric.WithDescription("Circuit breaker state changes by new state"),
+		metric.WithUnit("{transition}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metrics{
+		requestCounter:  requestCounter,
+		requestDuration: requestDuration,
+		retries:         retries,
+		breakerChanges:  breakerChanges,
+	}, nil
+}
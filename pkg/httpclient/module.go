@@ -0,0 +1,27 @@
+package httpclient
+
+import (
+	"context"
+
+	"project_template/pkg/logger"
+	"project_template/pkg/shutdown"
+
+	"go.uber.org/fx"
+)
+
+// Module provides *Clients, the instrumented HTTP clients of the
+// configured upstreams.
+var Module = fx.Module("httpclient",
+	logger.ForModule("httpclient"),
+	fx.Provide(NewClients),
+	fx.Invoke(registerShutdown),
+)
+
+// registerShutdown closes idle connections once requests and message
+// handlers no longer make outbound calls.
+func registerShutdown(clients *Clients, coordinator *shutdown.Coordinator) {
+	coordinator.Register(shutdown.PhaseBackground, "httpclient", func(context.Context) error {
+		clients.closeIdleConnections()
+		return nil
+	})
+}
@@ -0,0 +1,274 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"project_template/pkg/requestid"
+
+	"github.com/sony/gobreaker"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrCircuitOpen is returned, wrapped in a *url.Error, for requests to a
+// host whose circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// transport traces and measures each call and retries failed attempts.
+// Every attempt passes through the circuit breaker of its host.
+type transport struct {
+	upstream string
+	config   UpstreamConfig
+	next     http.RoundTripper
+	tracer   trace.Tracer
+	metrics  *metrics
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	breakers map[string]*gobreaker.TwoStepCircuitBreaker
+}
+
+func newTransport(upstream string, config UpstreamConfig, next http.RoundTripper, m *metrics, logger *slog.Logger) *transport {
+	return &transport{
+		upstream: upstream,
+		config:   config,
+		next:     next,
+		tracer:   otel.Tracer("http-client"),
+		metrics:  m,
+		logger:   logger,
+		breakers: make(map[string]*gobreaker.TwoStepCircuitBreaker),
+	}
+}
+
+func (t *transport) RoundTrip(r *http.Request) (*http.Response, error) {
+	start := time.Now()
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", r.Method),
+		attribute.String("server.address", r.URL.Host),
+		attribute.String("upstream", t.upstream),
+	}
+
+	ctx, span := t.tracer.Start(r.Context(), r.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+		trace.WithAttributes(attribute.String("url.full", r.URL.Redacted())),
+	)
+	defer span.End()
+
+	// A RoundTripper must not modify the caller's request.
+	req := r.Clone(ctx)
+	for k, v := range t.config.Headers {
+		if req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+	if id := requestid.FromContext(ctx); id != "" && req.Header.Get(requestid.Header) == "" {
+		req.Header.Set(requestid.Header, id)
+	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, attempts, err := t.retry(req)
+
+	if attempts > 1 {
+		span.SetAttributes(attribute.Int("http.request.resend_count", attempts-1))
+	}
+	switch {
+	case err != nil:
+		attrs = append(attrs, attribute.String("error.type", errorType(err)))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	default:
+		attrs = append(attrs, attribute.Int("http.response.status_code", resp.StatusCode))
+		span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+		if resp.StatusCode >= 400 {
+			span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+		}
+	}
+
+	t.metrics.requestCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
+	t.metrics.requestDuration.Record(ctx, time.Since(start).Seconds(), metric.WithAttributes(attrs...))
+	return resp, err
+}
+
+// retry sends req until it succeeds, fails in a way that is not worth
+// retrying or runs out of attempts, and returns the number of attempts.
+func (t *transport) retry(req *http.Request) (*http.Response, int, error) {
+	ctx := req.Context()
+	maxAttempts := max(t.config.Retry.MaxAttempts, 1)
+	if !replayable(req) {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, attempt, err
+			}
+			req.Body = body
+		}
+
+		resp, err := t.attempt(req)
+		if attempt == maxAttempts || !retryable(resp, err) || ctx.Err() != nil {
+			return resp, attempt, err
+		}
+
+		delay := backoff(t.config.Retry, attempt)
+		if resp != nil {
+			if after, ok := retryAfter(resp); ok {
+				if after > t.config.Retry.MaxBackoff {
+					return resp, attempt, nil
+				}
+				delay = max(delay, after)
+			}
+			// Drain the body so that the connection can be reused.
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		t.metrics.retries.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("upstream", t.upstream),
+		))
+		t.logger.DebugContext(ctx, "retrying HTTP request",
+			"upstream", t.upstream, "host", req.URL.Host, "attempt", attempt, "delay", delay, "error", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, attempt, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// attempt sends req once through the circuit breaker of its host.
+func (t *transport) attempt(req *http.Request) (*http.Response, error) {
+	if t.config.CircuitBreaker.Disabled {
+		return t.next.RoundTrip(req)
+	}
+
+	done, err := t.breaker(req.URL.Host).Allow()
+	if err != nil {
+		return nil, ErrCircuitOpen
+	}
+	resp, err := t.next.RoundTrip(req)
+	// Cancellation by the caller says nothing about the host's health.
+	if errors.Is(err, context.Canceled) {
+		done(true)
+		return resp, err
+	}
+	done(err == nil && resp.StatusCode < http.StatusInternalServerError)
+	return resp, err
+}
+
+func (t *transport) breaker(host string) *gobreaker.TwoStepCircuitBreaker {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if cb, ok := t.breakers[host]; ok {
+		return cb
+	}
+	failures := t.config.CircuitBreaker.Failures
+	cb := gobreaker.NewTwoStepCircuitBreaker(gobreaker.Settings{
+		Name:        host,
+		MaxRequests: 1,
+		Timeout:     t.config.CircuitBreaker.OpenTimeout,
+		ReadyToTrip: func(counts gobreaker.Counts) bool {
+			return counts.ConsecutiveFailures >= failures
+		},
+		OnStateChange: func(host string, from, to gobreaker.State) {
+			t.logger.Warn("HTTP circuit breaker state changed",
+				"upstream", t.upstream, "host", host, "from", from.String(), "to", to.String())
+			t.metrics.breakerChanges.Add(context.Background(), 1, metric.WithAttributes(
+				attribute.String("server.address", host),
+				attribute.String("upstream", t.upstream),
+				attribute.String("state", to.String()),
+			))
+		},
+	})
+	t.breakers[host] = cb
+	return cb
+}
+
+// replayable reports whether req may be sent again: it must be idempotent
+// and its body, if any, must be reproducible.
+func replayable(req *http.Request) bool {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get("Idempotency-Key") != ""
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrCircuitOpen) &&
+			!errors.Is(err, context.Canceled) &&
+			!errors.Is(err, context.DeadlineExceeded)
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff doubles the delay with each attempt, up to MaxBackoff, and
+// randomizes its upper half so that clients do not retry in lockstep.
+func backoff(cfg RetryConfig, attempt int) time.Duration {
+	delay := cfg.InitialBackoff << (attempt - 1)
+	if delay <= 0 || delay > cfg.MaxBackoff {
+		delay = cfg.MaxBackoff
+	}
+	half := delay / 2
+	return half + rand.N(half+1)
+}
+
+// retryAfter parses the Retry-After header of a 429 or 503 response.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+	return 0, false
+}
+
+func errorType(err error) string {
+	switch {
+	case errors.Is(err, ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	default:
+		return "error"
+	}
+}
@@ -0,0 +1,24 @@
+package logger
+
+import "time"
+
+type Config struct {
+	// Level is the minimum level logged: debug, info, warn or error.
+	Level string `mapstructure:"level" default:"info"`
+	// Format is the output format: json for production, text for
+	// plain key=value lines, pretty for colored output in development.
+	Format string `mapstructure:"format" default:"json"`
+	// Output is where records are written: stdout, stderr, or none to
+	// rely solely on OpenTelemetry export.
+	Output string `mapstructure:"output" default:"stdout"`
+	// Packages overrides Level for loggers created with Named, keyed by
+	// the name passed to it, e.g. {"messagebus": "debug"}.
+	Packages map[string]string `mapstructure:"packages"`
+	// DebugTimeout is how long debug logging enabled with SIGUSR1 lasts
+	// before the configured level is restored. Zero keeps it on until
+	// SIGUSR2.
+	DebugTimeout time.Duration `mapstructure:"debug_timeout" default:"15m"`
+	// AdminToken is the bearer token required by the log level admin
+	// endpoint. The endpoint is disabled if it is empty.
+	AdminToken string `mapstructure:"admin_token"`
+}
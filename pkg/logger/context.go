@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+)
+
+type userIDKey struct{}
+
+type attrsKey struct{}
+
+// WithUserID returns a copy of ctx carrying the ID of the authenticated
+// user, which is then added to records logged with ctx.
+func WithUserID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, userIDKey{}, id)
+}
+
+// UserID returns the user ID stored in ctx, or "" if there is none.
+func UserID(ctx context.Context) string {
+	id, _ := ctx.Value(userIDKey{}).(string)
+	return id
+}
+
+// With returns a copy of ctx carrying extra attributes that are added to
+// every record logged with it, so that values known early in a call chain,
+// such as the ID of the entity being processed, need not be repeated at
+// each log call. args are key-value pairs or slog.Attr values, as in
+// slog.Logger.With. An attribute replaces one with the same key already
+// in ctx.
+func With(ctx context.Context, args ...any) context.Context {
+	var r slog.Record
+	r.Add(args...)
+	var added []slog.Attr
+	r.Attrs(func(a slog.Attr) bool {
+		added = append(added, a)
+		return true
+	})
+
+	var attrs []slog.Attr
+	for _, a := range attrsFromContext(ctx) {
+		if !slices.ContainsFunc(added, func(b slog.Attr) bool { return b.Key == a.Key }) {
+			attrs = append(attrs, a)
+		}
+	}
+	return context.WithValue(ctx, attrsKey{}, append(attrs, added...))
+}
+
+func attrsFromContext(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
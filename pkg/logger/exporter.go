@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+)
+
+// Exporter forwards records to a handler attached after the logger is
+// built. It exists for the OpenTelemetry log bridge, which is set up by
+// pkg/telemetry and therefore cannot be passed to NewLogger: telemetry
+// itself needs a logger. Records logged before a handler is attached,
+// or while none is, are not exported.
+type Exporter struct {
+	handler atomic.Pointer[slog.Handler]
+}
+
+func NewExporter() *Exporter {
+	return &Exporter{}
+}
+
+// Attach starts forwarding records to h. A nil h stops exporting.
+func (e *Exporter) Attach(h slog.Handler) {
+	if h == nil {
+		e.handler.Store(nil)
+		return
+	}
+	e.handler.Store(&h)
+}
+
+// exportHandler is the slog.Handler side of an Exporter. Attributes and
+// groups added to it are recorded and replayed on the attached handler,
+// since it may be attached, or replaced, after loggers were derived.
+type exportHandler struct {
+	exporter *Exporter
+	ops      []func(slog.Handler) slog.Handler
+	cache    atomic.Pointer[exportCache]
+}
+
+// exportCache memoizes the attached handler with ops applied.
+type exportCache struct {
+	base    *slog.Handler
+	derived slog.Handler
+}
+
+func newExportHandler(e *Exporter) *exportHandler {
+	return &exportHandler{exporter: e}
+}
+
+func (h *exportHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	base := h.exporter.handler.Load()
+	return base != nil && (*base).Enabled(ctx, level)
+}
+
+func (h *exportHandler) Handle(ctx context.Context, record slog.Record) error {
+	if next := h.resolve(); next != nil {
+		return next.Handle(ctx, record)
+	}
+	return nil
+}
+
+func (h *exportHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *exportHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *exportHandler) with(op func(slog.Handler) slog.Handler) *exportHandler {
+	ops := append(h.ops[:len(h.ops):len(h.ops)], op)
+	return &exportHandler{exporter: h.exporter, ops: ops}
+}
+
+func (h *exportHandler) resolve() slog.Handler {
+	base := h.exporter.handler.Load()
+	if base == nil {
+		return nil
+	}
+	if c := h.cache.Load(); c != nil && c.base == base {
+		return c.derived
+	}
+	derived := *base
+	for _, op := range h.ops {
+		derived = op(derived)
+	}
+	h.cache.Store(&exportCache{base: base, derived: derived})
+	return derived
+}
@@ -0,0 +1,94 @@
+package logger
+
+import (
+	"context"
+	"log/slog"
+
+	"project_template/pkg/requestid"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// contextHandler filters records by level and adds correlation attributes
+// found in the context to every record logged with one of the *Context
+// methods. Records go to out, if set, and to export, if set.
+type contextHandler struct {
+	out      slog.Handler
+	export   slog.Handler
+	level    slog.Leveler
+	packages map[string]slog.Level
+}
+
+func newContextHandler(out, export slog.Handler, level slog.Leveler, packages map[string]slog.Level) *contextHandler {
+	return &contextHandler{
+		out:      out,
+		export:   export,
+		level:    level,
+		packages: packages,
+	}
+}
+
+func (h *contextHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := requestid.FromContext(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if id := UserID(ctx); id != "" {
+		record.AddAttrs(slog.String("user_id", id))
+	}
+	if attrs := attrsFromContext(ctx); len(attrs) > 0 {
+		record.AddAttrs(attrs...)
+	}
+
+	// The OpenTelemetry bridge correlates records with the active span
+	// itself, so trace and span IDs are only added for local output.
+	var exportErr error
+	if h.export != nil && h.export.Enabled(ctx, record.Level) {
+		exportErr = h.export.Handle(ctx, record.Clone())
+	}
+	if h.out == nil {
+		return exportErr
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		record.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+	if err := h.out.Handle(ctx, record); err != nil {
+		return err
+	}
+	return exportErr
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *contextHandler) with(apply func(slog.Handler) slog.Handler) *contextHandler {
+	derived := &contextHandler{level: h.level, packages: h.packages}
+	if h.out != nil {
+		derived.out = apply(h.out)
+	}
+	if h.export != nil {
+		derived.export = apply(h.export)
+	}
+	return derived
+}
+
+// named returns a handler for the named package, using its level
+// override if one is configured.
+func (h *contextHandler) named(name string) *contextHandler {
+	named := h.WithAttrs([]slog.Attr{slog.String("logger", name)}).(*contextHandler)
+	if level, ok := h.packages[name]; ok {
+		named.level = level
+	}
+	return named
+}
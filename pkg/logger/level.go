@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// LevelController holds the default log level and lets it be changed at
+// runtime, optionally reverting to the configured level after a while so
+// that a forgotten debug switch does not flood the logs.
+//
+// Loggers with a level override in Config.Packages are not affected.
+type LevelController struct {
+	mu       sync.Mutex
+	level    slog.LevelVar
+	base     slog.Level
+	revertAt time.Time
+	timer    *time.Timer
+}
+
+// LevelState describes the current level and when it reverts.
+// RevertAt is zero if the level is the configured one or was set
+// without a timeout.
+type LevelState struct {
+	Level    slog.Level `json:"level"`
+	Base     slog.Level `json:"base"`
+	RevertAt time.Time  `json:"revert_at,omitzero"`
+}
+
+func NewLevelController(config Config) (*LevelController, error) {
+	base, err := ParseLevel(config.Level)
+	if err != nil {
+		return nil, err
+	}
+	c := &LevelController{base: base}
+	c.level.Set(base)
+	return c, nil
+}
+
+// Level implements slog.Leveler.
+func (c *LevelController) Level() slog.Level {
+	return c.level.Level()
+}
+
+// Set changes the level. If revertAfter is positive, the configured level
+// is restored once it elapses.
+func (c *LevelController) Set(level slog.Level, revertAfter time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopTimer()
+	c.level.Set(level)
+	if revertAfter > 0 && level != c.base {
+		c.revertAt = time.Now().Add(revertAfter)
+		var timer *time.Timer
+		timer = time.AfterFunc(revertAfter, func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			// Ignore a revert that raced with a newer Set or Reset.
+			if c.timer == timer {
+				c.stopTimer()
+				c.level.Set(c.base)
+			}
+		})
+		c.timer = timer
+	}
+}
+
+// Reset restores the configured level.
+func (c *LevelController) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.stopTimer()
+	c.level.Set(c.base)
+}
+
+func (c *LevelController) State() LevelState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return LevelState{
+		Level:    c.level.Level(),
+		Base:     c.base,
+		RevertAt: c.revertAt,
+	}
+}
+
+func (c *LevelController) stopTimer() {
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	c.revertAt = time.Time{}
+}
@@ -1,10 +1,82 @@
 package logger
 
 import (
+	"fmt"
+	"io"
 	"log/slog"
 	"os"
+	"strings"
 )
 
-func NewLogger() *slog.Logger {
-	return slog.New(slog.NewJSONHandler(os.Stdout, nil))
+// NewLogger creates the application logger. Records logged with one of
+// the *Context methods carry the trace, span, request and user IDs found
+// in the context. Its default level is taken from levels. Records are
+// written to the configured output and forwarded to exporter.
+func NewLogger(config Config, levels *LevelController, exporter *Exporter) (*slog.Logger, error) {
+	var w io.Writer
+	switch strings.ToLower(config.Output) {
+	case "stdout", "":
+		w = os.Stdout
+	case "stderr":
+		w = os.Stderr
+	case "none":
+	default:
+		return nil, fmt.Errorf("unknown log output %q", config.Output)
+	}
+	return newLogger(w, config, levels, exporter)
+}
+
+func newLogger(w io.Writer, config Config, levels *LevelController, exporter *Exporter) (*slog.Logger, error) {
+	packages := make(map[string]slog.Level, len(config.Packages))
+	for name, l := range config.Packages {
+		lvl, err := ParseLevel(l)
+		if err != nil {
+			return nil, fmt.Errorf("logger.packages.%s: %w", name, err)
+		}
+		packages[name] = lvl
+	}
+
+	// Filtering happens in contextHandler so that named loggers can be
+	// more verbose than the default level; the output handler accepts all.
+	opts := &slog.HandlerOptions{Level: slog.Level(-8)}
+	var out slog.Handler
+	switch strings.ToLower(config.Format) {
+	case "json", "":
+		out = slog.NewJSONHandler(w, opts)
+	case "text":
+		out = slog.NewTextHandler(w, opts)
+	case "pretty":
+		out = newPrettyHandler(w)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", config.Format)
+	}
+	if w == nil {
+		out = nil
+	}
+
+	var export slog.Handler
+	if exporter != nil {
+		export = newExportHandler(exporter)
+	}
+
+	return slog.New(newContextHandler(out, export, levels, packages)), nil
+}
+
+// ParseLevel parses a level name such as "debug" or "warn".
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q", s)
+	}
+	return level, nil
+}
+
+// Named returns a logger for the named package. Its records carry a
+// "logger" attribute and are filtered by the level configured for name
+// in Config.Packages, falling back to the default level.
+func Named(l *slog.Logger, name string) *slog.Logger {
+	if h, ok := l.Handler().(*contextHandler); ok {
+		return slog.New(h.named(name))
+	}
+	return l.With("logger", name)
 }
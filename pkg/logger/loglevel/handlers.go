@@ -0,0 +1,120 @@
+package loglevel
+
+import (
+	"crypto/subtle"
+	"encoding/json/v2"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/pkg/logger"
+)
+
+// SetLevelRequest is the body of PUT /api/admin/log-level.
+type SetLevelRequest struct {
+	// Level is debug, info, warn or error.
+	Level string `json:"level"`
+	// Duration, e.g. "10m", reverts the level to the configured one once
+	// it elapses and must be positive. If empty, the level stays until
+	// changed again.
+	Duration string `json:"duration,omitempty"`
+}
+
+// GetLevelHandler handles GET /api/admin/log-level
+type GetLevelHandler struct {
+	levels *logger.LevelController
+	token  string
+}
+
+func NewGetLevelHandler(levels *logger.LevelController, config logger.Config) *GetLevelHandler {
+	return &GetLevelHandler{
+		levels: levels,
+		token:  config.AdminToken,
+	}
+}
+
+func (*GetLevelHandler) Pattern() string {
+	return "GET /api/admin/log-level"
+}
+
+// RequiresAuth is false because the handlers check the admin token
+// themselves.
+func (*GetLevelHandler) RequiresAuth() bool {
+	return false
+}
+
+func (h *GetLevelHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	if err := authorize(r, h.token); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.MarshalWrite(w, h.levels.State())
+}
+
+// SetLevelHandler handles PUT /api/admin/log-level
+type SetLevelHandler struct {
+	logger *slog.Logger
+	levels *logger.LevelController
+	token  string
+}
+
+func NewSetLevelHandler(logger *slog.Logger, levels *logger.LevelController, config logger.Config) *SetLevelHandler {
+	return &SetLevelHandler{
+		logger: logger,
+		levels: levels,
+		token:  config.AdminToken,
+	}
+}
+
+func (*SetLevelHandler) Pattern() string {
+	return "PUT /api/admin/log-level"
+}
+
+func (*SetLevelHandler) RequiresAuth() bool {
+	return false
+}
+
+func (h *SetLevelHandler) Handle(w http.ResponseWriter, r *http.Request) error {
+	if err := authorize(r, h.token); err != nil {
+		return err
+	}
+
+	var req SetLevelRequest
+	if err := json.UnmarshalRead(r.Body, &req); err != nil {
+		return apperrors.NewBadRequest("invalid request body")
+	}
+
+	level, err := logger.ParseLevel(req.Level)
+	if err != nil {
+		return apperrors.NewBadRequest(err.Error())
+	}
+	var duration time.Duration
+	if req.Duration != "" {
+		duration, err = time.ParseDuration(req.Duration)
+		if err != nil || duration <= 0 {
+			return apperrors.NewBadRequest("duration must be a positive duration such as 10m")
+		}
+	}
+
+	h.levels.Set(level, duration)
+	h.logger.InfoContext(r.Context(), "log level changed", "level", level.String(), "duration", duration)
+
+	w.Header().Set("Content-Type", "application/json")
+	return json.MarshalWrite(w, h.levels.State())
+}
+
+// authorize checks the bearer token. The endpoints are hidden when no
+// token is configured.
+func authorize(r *http.Request, token string) error {
+	if token == "" {
+		return apperrors.NewNotFound("endpoint")
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		return apperrors.NewUnauthorized("invalid admin token")
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package loglevel
+
+import (
+	"project_template/pkg/webserver"
+
+	"go.uber.org/fx"
+)
+
+// Module exposes the runtime log level over HTTP. It lives apart from
+// package logger because the webserver itself depends on the logger.
+var Module = fx.Module("loglevel",
+	fx.Provide(
+		webserver.AsAppRoute(NewGetLevelHandler),
+		webserver.AsAppRoute(NewSetLevelHandler),
+	),
+)
@@ -1,11 +1,30 @@
 package logger
 
 import (
+	"log/slog"
+
 	"go.uber.org/fx"
 )
 
 var Module = fx.Module("logger",
 	fx.Provide(
+		NewLevelController,
+		NewExporter,
 		NewLogger,
 	),
+	fx.Invoke(watchSignals),
 )
+
+// ForModule decorates the logger for the enclosing fx module with Named,
+// so the module's records are tagged with name and honour its level
+// override:
+//
+//	var Module = fx.Module("messagebus",
+//		logger.ForModule("messagebus"),
+//		...
+//	)
+func ForModule(name string) fx.Option {
+	return fx.Decorate(func(l *slog.Logger) *slog.Logger {
+		return Named(l, name)
+	})
+}
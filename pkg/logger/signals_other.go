@@ -0,0 +1,6 @@
+//go:build !unix
+
+package logger
+
+// watchSignals is a no-op on platforms without SIGUSR1 and SIGUSR2.
+func watchSignals() {}
@@ -0,0 +1,46 @@
+//go:build unix
+
+package logger
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/fx"
+)
+
+// watchSignals switches the default level to debug on SIGUSR1, for
+// Config.DebugTimeout, and back to the configured level on SIGUSR2.
+func watchSignals(lc fx.Lifecycle, levels *LevelController, logger *slog.Logger, config Config) {
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			signal.Notify(signals, syscall.SIGUSR1, syscall.SIGUSR2)
+			go func() {
+				defer close(done)
+				for sig := range signals {
+					switch sig {
+					case syscall.SIGUSR1:
+						levels.Set(slog.LevelDebug, config.DebugTimeout)
+						logger.Info("debug logging enabled", "signal", sig.String(), "timeout", config.DebugTimeout)
+					case syscall.SIGUSR2:
+						levels.Reset()
+						logger.Info("log level reset", "signal", sig.String(), "level", levels.Level().String())
+					}
+				}
+			}()
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			signal.Stop(signals)
+			close(signals)
+			<-done
+			return nil
+		},
+	})
+}
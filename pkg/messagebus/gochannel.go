@@ -7,6 +7,8 @@ import (
 	"log/slog"
 	"sync"
 
+	"project_template/pkg/requestid"
+
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/ThreeDotsLabs/watermill/message/router/middleware"
@@ -60,12 +62,17 @@ func (b *goChannelBus) Publish(ctx context.Context, event Event) error {
 
 	msg := message.NewMessage(watermill.NewUUID(), payload)
 	msg.SetContext(ctx)
+	// The request ID travels as the correlation ID so that handler logs
+	// can be tied to the request that published the event.
+	if id := requestid.FromContext(ctx); id != "" {
+		middleware.SetCorrelationID(id, msg)
+	}
 
 	if err := b.pubSub.Publish(event.Topic(), msg); err != nil {
 		return fmt.Errorf("failed to publish message: %w", err)
 	}
 
-	b.logger.Debug("published event", "topic", event.Topic(), "uuid", msg.UUID)
+	b.logger.DebugContext(ctx, "published event", "topic", event.Topic(), "uuid", msg.UUID)
 	return nil
 }
 
@@ -85,8 +92,11 @@ func (b *goChannelBus) Subscribe(handler Handler) error {
 		b.pubSub,
 		func(msg *message.Message) error {
 			ctx := msg.Context()
+			if id := middleware.MessageCorrelationID(msg); id != "" {
+				ctx = requestid.NewContext(ctx, id)
+			}
 			if err := handler.Handle(ctx, msg.Payload); err != nil {
-				b.logger.Error("handler error", "topic", handler.Topic(), "error", err)
+				b.logger.ErrorContext(ctx, "handler error", "topic", handler.Topic(), "error", err)
 				return err
 			}
 			return nil
@@ -107,6 +117,10 @@ func (b *goChannelBus) Run(ctx context.Context) error {
 	return b.router.Run(ctx)
 }
 
+func (b *goChannelBus) IsRunning() bool {
+	return b.router.IsRunning()
+}
+
 func (b *goChannelBus) Close() error {
 	if err := b.router.Close(); err != nil {
 		return fmt.Errorf("failed to close router: %w", err)
@@ -0,0 +1,19 @@
+package messagebus
+
+import (
+	"context"
+	"errors"
+
+	"project_template/pkg/health"
+)
+
+// NewHealthChecker reports the instance as not ready while the message
+// bus router is not running, i.e. events would not be consumed.
+func NewHealthChecker(bus MessageBus) health.Checker {
+	return health.NewCheckerFunc("messagebus", health.Readiness, func(context.Context) error {
+		if !bus.IsRunning() {
+			return errors.New("router is not running")
+		}
+		return nil
+	})
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"log/slog"
 
+	"project_template/pkg/health"
+	"project_template/pkg/logger"
+	"project_template/pkg/shutdown"
+
 	"go.uber.org/fx"
 )
 
@@ -24,6 +28,7 @@ func AsHandler(f any) any {
 
 // Module provides the message bus dependencies.
 var Module = fx.Module("messagebus",
+	logger.ForModule("messagebus"),
 	fx.Provide(
 		fx.Annotate(
 			NewMessageBus,
@@ -31,6 +36,7 @@ var Module = fx.Module("messagebus",
 			fx.As(new(Publisher)),
 			fx.As(new(Subscriber)),
 		),
+		health.AsChecker(NewHealthChecker),
 	),
 	fx.Invoke(registerHandlers),
 	fx.Invoke(startRouter),
@@ -57,7 +63,9 @@ func registerHandlers(bus MessageBus, params HandlerParams) error {
 }
 
 // startRouter starts the message bus router in a goroutine managed by fx lifecycle.
-func startRouter(lc fx.Lifecycle, bus MessageBus, logger *slog.Logger) {
+// The bus is closed by the shutdown coordinator once HTTP requests, which may
+// still publish events, have drained.
+func startRouter(lc fx.Lifecycle, bus MessageBus, logger *slog.Logger, coordinator *shutdown.Coordinator) {
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
 			go func() {
@@ -67,8 +75,19 @@ func startRouter(lc fx.Lifecycle, bus MessageBus, logger *slog.Logger) {
 			}()
 			return nil
 		},
-		OnStop: func(ctx context.Context) error {
-			return bus.Close()
-		},
+	})
+
+	coordinator.Register(shutdown.PhaseMessageBus, "messagebus", func(ctx context.Context) error {
+		logger.Info("Closing message bus")
+		done := make(chan error, 1)
+		go func() {
+			done <- bus.Close()
+		}()
+		select {
+		case err := <-done:
+			return err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
 	})
 }
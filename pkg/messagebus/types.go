@@ -52,6 +52,8 @@ type Subscriber interface {
 	Subscribe(handler Handler) error
 	// Run starts processing messages. Blocks until context is canceled.
 	Run(ctx context.Context) error
+	// IsRunning reports whether messages are being processed.
+	IsRunning() bool
 	// Close closes the subscriber.
 	Close() error
 }
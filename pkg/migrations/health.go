@@ -0,0 +1,23 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+
+	"project_template/pkg/health"
+)
+
+// NewHealthChecker reports the instance as not ready while the database
+// schema is behind the migrations embedded in the binary.
+func NewHealthChecker(m *Migrator) health.Checker {
+	return health.NewCheckerFunc("migrations", health.Readiness, func(ctx context.Context) error {
+		pending, err := m.Pending(ctx)
+		if err != nil {
+			return err
+		}
+		if pending > 0 {
+			return fmt.Errorf("%d pending migrations", pending)
+		}
+		return nil
+	})
+}
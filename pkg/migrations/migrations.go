@@ -1,9 +1,12 @@
 package migrations
 
 import (
+	"context"
 	"database/sql"
 	"embed"
+	"errors"
 	"fmt"
+	"io/fs"
 	"log/slog"
 
 	"github.com/pressly/goose/v3"
@@ -12,66 +15,87 @@ import (
 //go:embed sql/*.sql
 var embedMigrations embed.FS
 
+// Migrator runs the embedded migrations through a goose provider of its
+// own, so that it does not depend on goose's process-wide state and is
+// safe for concurrent use, such as by readiness probes.
 type Migrator struct {
-	db     *sql.DB
-	logger *slog.Logger
-	config Config
+	provider *goose.Provider
+	logger   *slog.Logger
+	config   Config
 }
 
-func NewMigrator(db *sql.DB, logger *slog.Logger, config Config) *Migrator {
-	return &Migrator{
-		db:     db,
-		logger: logger,
-		config: config,
+func NewMigrator(db *sql.DB, logger *slog.Logger, config Config) (*Migrator, error) {
+	fsys, err := fs.Sub(embedMigrations, "sql")
+	if err != nil {
+		return nil, err
+	}
+	provider, err := goose.NewProvider(goose.DialectPostgres, db, fsys,
+		goose.WithDisableGlobalRegistry(true),
+		goose.WithSlog(logger),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migration provider: %w", err)
 	}
+
+	return &Migrator{
+		provider: provider,
+		logger:   logger,
+		config:   config,
+	}, nil
 }
 
 func (m *Migrator) Up() error {
-	goose.SetLogger(goose.NopLogger())
-
-	goose.SetBaseFS(embedMigrations)
-
-	if err := goose.SetDialect("postgres"); err != nil {
-		return fmt.Errorf("failed to set goose dialect: %w", err)
-	}
-
-	if err := goose.Up(m.db, "sql"); err != nil {
+	results, err := m.provider.Up(context.Background())
+	if err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
-	m.logger.Info("database migrations completed successfully")
+	m.logger.Info("database migrations completed successfully", "applied", len(results))
 	return nil
 }
 
 func (m *Migrator) Down() error {
-	goose.SetLogger(goose.NopLogger())
-
-	goose.SetBaseFS(embedMigrations)
-
-	if err := goose.SetDialect("postgres"); err != nil {
-		return fmt.Errorf("failed to set goose dialect: %w", err)
-	}
-
-	if err := goose.Down(m.db, "sql"); err != nil {
+	result, err := m.provider.Down(context.Background())
+	if err != nil {
+		if errors.Is(err, goose.ErrNoNextVersion) {
+			m.logger.Info("no database migration to roll back")
+			return nil
+		}
 		return fmt.Errorf("failed to rollback migration: %w", err)
 	}
 
-	m.logger.Info("database migration rolled back successfully")
+	m.logger.Info("database migration rolled back successfully", "version", result.Source.Version)
 	return nil
 }
 
 func (m *Migrator) Status() error {
-	goose.SetLogger(goose.NopLogger())
-
-	goose.SetBaseFS(embedMigrations)
+	statuses, err := m.provider.Status(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get migration status: %w", err)
+	}
 
-	if err := goose.SetDialect("postgres"); err != nil {
-		return fmt.Errorf("failed to set goose dialect: %w", err)
+	for _, s := range statuses {
+		args := []any{"version", s.Source.Version, "path", s.Source.Path, "state", s.State}
+		if s.State == goose.StateApplied {
+			args = append(args, "applied_at", s.AppliedAt)
+		}
+		m.logger.Info("migration", args...)
 	}
+	return nil
+}
 
-	if err := goose.Status(m.db, "sql"); err != nil {
-		return fmt.Errorf("failed to get migration status: %w", err)
+// Pending returns the number of embedded migrations not yet applied.
+func (m *Migrator) Pending(ctx context.Context) (int, error) {
+	current, _, err := m.provider.GetVersions(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get database version: %w", err)
 	}
 
-	return nil
+	pending := 0
+	for _, s := range m.provider.ListSources() {
+		if s.Version > current {
+			pending++
+		}
+	}
+	return pending, nil
 }
@@ -3,18 +3,23 @@ package migrations
 import (
 	"log/slog"
 
+	"project_template/pkg/health"
+	"project_template/pkg/logger"
+
 	"go.uber.org/fx"
 	"gorm.io/gorm"
 )
 
 var Module = fx.Module("migrations",
+	logger.ForModule("migrations"),
 	fx.Provide(func(db *gorm.DB, logger *slog.Logger, config Config) (*Migrator, error) {
 		sqlDB, err := db.DB()
 		if err != nil {
 			return nil, err
 		}
-		return NewMigrator(sqlDB, logger, config), nil
+		return NewMigrator(sqlDB, logger, config)
 	}),
+	fx.Provide(health.AsChecker(NewHealthChecker)),
 	fx.Invoke(RunMigrations),
 )
 
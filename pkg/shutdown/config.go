@@ -0,0 +1,17 @@
+package shutdown
+
+import "time"
+
+type Config struct {
+	// DrainPeriod is how long the instance keeps serving after readiness
+	// starts failing, so load balancers can stop routing new requests to it.
+	DrainPeriod time.Duration `mapstructure:"drain_period" default:"5s"`
+	// HTTPTimeout bounds waiting for in-flight HTTP requests.
+	HTTPTimeout time.Duration `mapstructure:"http_timeout" default:"15s"`
+	// MessageBusTimeout bounds waiting for running message handlers.
+	MessageBusTimeout time.Duration `mapstructure:"messagebus_timeout" default:"10s"`
+	// BackgroundTimeout bounds stopping background jobs.
+	BackgroundTimeout time.Duration `mapstructure:"background_timeout" default:"10s"`
+	// TelemetryTimeout bounds flushing buffered spans and metrics.
+	TelemetryTimeout time.Duration `mapstructure:"telemetry_timeout" default:"5s"`
+}
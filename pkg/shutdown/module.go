@@ -0,0 +1,17 @@
+package shutdown
+
+import (
+	"project_template/pkg/logger"
+
+	"go.uber.org/fx"
+)
+
+var Module = fx.Module("shutdown",
+	logger.ForModule("shutdown"),
+	fx.Provide(
+		NewCoordinator,
+	),
+	// Construct the coordinator eagerly so that its OnStop hook is appended
+	// before, and therefore runs after, the hooks of the other modules.
+	fx.Invoke(func(*Coordinator) {}),
+)
@@ -0,0 +1,153 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.uber.org/fx"
+)
+
+// Phase is a step of the shutdown sequence. Phases run in declaration
+// order; hooks within a phase run in registration order.
+type Phase int
+
+const (
+	// PhaseDrain stops advertising readiness. The coordinator then waits
+	// Config.DrainPeriod while the instance keeps serving traffic.
+	PhaseDrain Phase = iota
+	// PhaseHTTP stops accepting connections and waits for in-flight requests.
+	PhaseHTTP
+	// PhaseMessageBus waits for running message handlers and closes the bus.
+	PhaseMessageBus
+	// PhaseBackground stops background jobs and releases what requests and
+	// message handlers use, such as outbound HTTP connections.
+	PhaseBackground
+	// PhaseTelemetry flushes buffered spans and metrics.
+	PhaseTelemetry
+)
+
+var phases = []Phase{PhaseDrain, PhaseHTTP, PhaseMessageBus, PhaseBackground, PhaseTelemetry}
+
+func (p Phase) String() string {
+	switch p {
+	case PhaseDrain:
+		return "drain"
+	case PhaseHTTP:
+		return "http"
+	case PhaseMessageBus:
+		return "messagebus"
+	case PhaseBackground:
+		return "background"
+	case PhaseTelemetry:
+		return "telemetry"
+	default:
+		return fmt.Sprintf("phase(%d)", int(p))
+	}
+}
+
+type hook struct {
+	phase Phase
+	name  string
+	stop  func(ctx context.Context) error
+}
+
+// Coordinator runs shutdown hooks registered by modules in a fixed phase
+// order, independent of the order in which fx constructed the modules.
+// Modules register with the coordinator instead of appending OnStop hooks
+// for anything that must happen in sequence.
+type Coordinator struct {
+	logger *slog.Logger
+	config Config
+
+	mu    sync.Mutex
+	hooks []hook
+}
+
+func NewCoordinator(lc fx.Lifecycle, logger *slog.Logger, config Config) *Coordinator {
+	c := &Coordinator{
+		logger: logger,
+		config: config,
+	}
+
+	// The coordinator is constructed before the modules that depend on it,
+	// so this hook runs after their own OnStop hooks.
+	lc.Append(fx.Hook{
+		OnStop: c.Shutdown,
+	})
+
+	return c
+}
+
+// Register adds a hook to run during phase.
+func (c *Coordinator) Register(phase Phase, name string, stop func(ctx context.Context) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, hook{phase: phase, name: name, stop: stop})
+}
+
+// Shutdown runs all phases in order. A failing hook does not prevent
+// later phases from running; all errors are returned joined.
+func (c *Coordinator) Shutdown(ctx context.Context) error {
+	c.mu.Lock()
+	hooks := append([]hook(nil), c.hooks...)
+	c.mu.Unlock()
+
+	var errs []error
+	for _, phase := range phases {
+		c.logger.Info("shutdown phase started", "phase", phase.String())
+		start := time.Now()
+
+		phaseCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout := c.timeout(phase); timeout > 0 {
+			phaseCtx, cancel = context.WithTimeout(ctx, timeout)
+		}
+		for _, h := range hooks {
+			if h.phase != phase {
+				continue
+			}
+			if err := h.stop(phaseCtx); err != nil {
+				c.logger.Error("shutdown hook failed", "phase", phase.String(), "hook", h.name, "error", err)
+				errs = append(errs, fmt.Errorf("%s: %w", h.name, err))
+			}
+		}
+		cancel()
+
+		if phase == PhaseDrain && c.config.DrainPeriod > 0 {
+			c.logger.Info("draining", "period", c.config.DrainPeriod)
+			select {
+			case <-time.After(c.config.DrainPeriod):
+			case <-ctx.Done():
+				errs = append(errs, fmt.Errorf("drain: %w", ctx.Err()))
+			}
+		}
+
+		c.logger.Info("shutdown phase completed", "phase", phase.String(), "duration", time.Since(start))
+	}
+
+	return errors.Join(errs...)
+}
+
+// timeout returns the time budget of phase, or 0 if it has none of its own.
+func (c *Coordinator) timeout(phase Phase) time.Duration {
+	switch phase {
+	case PhaseHTTP:
+		return c.config.HTTPTimeout
+	case PhaseMessageBus:
+		return c.config.MessageBusTimeout
+	case PhaseBackground:
+		return c.config.BackgroundTimeout
+	case PhaseTelemetry:
+		return c.config.TelemetryTimeout
+	default:
+		return 0
+	}
+}
+
+// TotalTimeout is the longest the shutdown sequence can take with config.
+func (c Config) TotalTimeout() time.Duration {
+	return c.DrainPeriod + c.HTTPTimeout + c.MessageBusTimeout + c.BackgroundTimeout + c.TelemetryTimeout
+}
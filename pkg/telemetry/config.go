@@ -1,8 +1,69 @@
 package telemetry
 
+import "time"
+
+// Exporter values for Config.Exporter.
+const (
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// Protocol values for Config.Protocol.
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http/protobuf"
+)
+
+// Config configures telemetry export. The standard OTEL_* environment
+// variables take precedence over the matching fields: OTEL_SDK_DISABLED,
+// OTEL_SERVICE_NAME, OTEL_RESOURCE_ATTRIBUTES, OTEL_TRACES_SAMPLER(_ARG),
+// OTEL_EXPORTER_OTLP_PROTOCOL, and the endpoint, header and timeout
+// variables read by the OTLP exporters. The signal-specific variants, such
+// as OTEL_EXPORTER_OTLP_TRACES_PROTOCOL, take precedence over the general
+// ones.
 type Config struct {
 	Enabled      bool   `mapstructure:"enabled" default:"false"`
 	ServiceName  string `mapstructure:"service_name" default:"project_template"`
 	OTLPEndpoint string `mapstructure:"otlp_endpoint" default:"localhost:4317"`
 	Insecure     bool   `mapstructure:"insecure" default:"true"`
+	// Logs exports application logs over OTLP alongside traces and metrics.
+	Logs bool `mapstructure:"logs" default:"true"`
+
+	// ServiceVersion and Environment become the service.version and
+	// deployment.environment resource attributes.
+	ServiceVersion string `mapstructure:"service_version"`
+	Environment    string `mapstructure:"environment"`
+	// ResourceAttributes are added to the resource as is.
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
+
+	// Exporter is otlp, stdout or file. stdout and file write
+	// human-readable JSON for local debugging.
+	Exporter string `mapstructure:"exporter" default:"otlp"`
+	// Protocol is the OTLP transport: grpc or http/protobuf.
+	Protocol string `mapstructure:"protocol" default:"grpc"`
+	// Headers are sent with every OTLP request, e.g. an API key for an
+	// authenticated collector.
+	Headers map[string]string `mapstructure:"headers"`
+	// FilePath is where the file exporter writes.
+	FilePath string `mapstructure:"file_path" default:"telemetry.jsonl"`
+
+	// SampleRatio is the fraction of new traces sampled. Spans with a
+	// parent follow the parent's decision.
+	SampleRatio float64 `mapstructure:"sample_ratio" default:"1"`
+	// MetricInterval is how often metrics are exported.
+	MetricInterval time.Duration `mapstructure:"metric_interval" default:"15s"`
+
+	// MaxQueueSize bounds the spans and log records buffered for export.
+	// Further records are dropped, and counted as dropped, while the
+	// collector is slow or unreachable.
+	MaxQueueSize int `mapstructure:"max_queue_size" default:"2048"`
+	// ExportTimeout bounds a single OTLP export request.
+	ExportTimeout time.Duration `mapstructure:"export_timeout" default:"10s"`
+	// RetryMaxElapsed is how long a failed OTLP export is retried before
+	// its data is dropped.
+	RetryMaxElapsed time.Duration `mapstructure:"retry_max_elapsed" default:"1m"`
+
+	// Prometheus exposes metrics for scraping, with or without push export.
+	Prometheus PrometheusConfig `mapstructure:"prometheus"`
 }
@@ -0,0 +1,323 @@
+package telemetry
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// exporters are the span, metric and log exporters selected by Config.
+// log is nil unless Config.Logs is set.
+type exporters struct {
+	span   sdktrace.SpanExporter
+	metric sdkmetric.Exporter
+	log    sdklog.Exporter
+	// closer releases the output of the file exporter after the
+	// providers have been shut down.
+	closer io.Closer
+}
+
+func newExporters(ctx context.Context, cfg Config) (*exporters, error) {
+	switch cfg.Exporter {
+	case ExporterOTLP, "":
+		return newOTLPExporters(ctx, cfg)
+	case ExporterStdout:
+		return newWriterExporters(cfg, os.Stdout, true)
+	case ExporterFile:
+		f, err := os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open telemetry file: %w", err)
+		}
+		exp, err := newWriterExporters(cfg, f, false)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		exp.closer = f
+		return exp, nil
+	default:
+		return nil, fmt.Errorf("unknown telemetry exporter %q", cfg.Exporter)
+	}
+}
+
+// newOTLPExporters creates an OTLP exporter per signal, each over the
+// protocol set for it.
+func newOTLPExporters(ctx context.Context, cfg Config) (*exporters, error) {
+	spanExp, err := newOTLPSpanExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	metricExp, err := newOTLPMetricExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	}
+
+	exp := &exporters{span: spanExp, metric: metricExp}
+	if !cfg.Logs {
+		return exp, nil
+	}
+	exp.log, err = newOTLPLogExporter(ctx, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP log exporter: %w", err)
+	}
+	return exp, nil
+}
+
+func newOTLPSpanExporter(ctx context.Context, cfg Config) (sdktrace.SpanExporter, error) {
+	const signal = "TRACES"
+	switch protocol := protocolFor(signal, cfg); protocol {
+	case ProtocolGRPC:
+		var opts []otlptracegrpc.Option
+		if !endpointFromEnv(signal) {
+			opts = append(opts, otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint))
+			if cfg.Insecure {
+				opts = append(opts, otlptracegrpc.WithInsecure())
+			}
+		}
+		if len(cfg.Headers) > 0 && !headersFromEnv(signal) {
+			opts = append(opts, otlptracegrpc.WithHeaders(cfg.Headers))
+		}
+		opts = append(opts, otlptracegrpc.WithRetry(otlptracegrpc.RetryConfig(newRetryPolicy(cfg))))
+		if !timeoutFromEnv(signal) {
+			opts = append(opts, otlptracegrpc.WithTimeout(cfg.ExportTimeout))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+	case ProtocolHTTP:
+		var opts []otlptracehttp.Option
+		if !endpointFromEnv(signal) {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.OTLPEndpoint))
+			if cfg.Insecure {
+				opts = append(opts, otlptracehttp.WithInsecure())
+			}
+		}
+		if len(cfg.Headers) > 0 && !headersFromEnv(signal) {
+			opts = append(opts, otlptracehttp.WithHeaders(cfg.Headers))
+		}
+		opts = append(opts, otlptracehttp.WithRetry(otlptracehttp.RetryConfig(newRetryPolicy(cfg))))
+		if !timeoutFromEnv(signal) {
+			opts = append(opts, otlptracehttp.WithTimeout(cfg.ExportTimeout))
+		}
+		return otlptracehttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", protocol)
+	}
+}
+
+func newOTLPMetricExporter(ctx context.Context, cfg Config) (sdkmetric.Exporter, error) {
+	const signal = "METRICS"
+	switch protocol := protocolFor(signal, cfg); protocol {
+	case ProtocolGRPC:
+		var opts []otlpmetricgrpc.Option
+		if !endpointFromEnv(signal) {
+			opts = append(opts, otlpmetricgrpc.WithEndpoint(cfg.OTLPEndpoint))
+			if cfg.Insecure {
+				opts = append(opts, otlpmetricgrpc.WithInsecure())
+			}
+		}
+		if len(cfg.Headers) > 0 && !headersFromEnv(signal) {
+			opts = append(opts, otlpmetricgrpc.WithHeaders(cfg.Headers))
+		}
+		opts = append(opts, otlpmetricgrpc.WithRetry(otlpmetricgrpc.RetryConfig(newRetryPolicy(cfg))))
+		if !timeoutFromEnv(signal) {
+			opts = append(opts, otlpmetricgrpc.WithTimeout(cfg.ExportTimeout))
+		}
+		return otlpmetricgrpc.New(ctx, opts...)
+	case ProtocolHTTP:
+		var opts []otlpmetrichttp.Option
+		if !endpointFromEnv(signal) {
+			opts = append(opts, otlpmetrichttp.WithEndpoint(cfg.OTLPEndpoint))
+			if cfg.Insecure {
+				opts = append(opts, otlpmetrichttp.WithInsecure())
+			}
+		}
+		if len(cfg.Headers) > 0 && !headersFromEnv(signal) {
+			opts = append(opts, otlpmetrichttp.WithHeaders(cfg.Headers))
+		}
+		opts = append(opts, otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig(newRetryPolicy(cfg))))
+		if !timeoutFromEnv(signal) {
+			opts = append(opts, otlpmetrichttp.WithTimeout(cfg.ExportTimeout))
+		}
+		return otlpmetrichttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", protocol)
+	}
+}
+
+func newOTLPLogExporter(ctx context.Context, cfg Config) (sdklog.Exporter, error) {
+	const signal = "LOGS"
+	switch protocol := protocolFor(signal, cfg); protocol {
+	case ProtocolGRPC:
+		var opts []otlploggrpc.Option
+		if !endpointFromEnv(signal) {
+			opts = append(opts, otlploggrpc.WithEndpoint(cfg.OTLPEndpoint))
+			if cfg.Insecure {
+				opts = append(opts, otlploggrpc.WithInsecure())
+			}
+		}
+		if len(cfg.Headers) > 0 && !headersFromEnv(signal) {
+			opts = append(opts, otlploggrpc.WithHeaders(cfg.Headers))
+		}
+		opts = append(opts, otlploggrpc.WithRetry(otlploggrpc.RetryConfig(newRetryPolicy(cfg))))
+		if !timeoutFromEnv(signal) {
+			opts = append(opts, otlploggrpc.WithTimeout(cfg.ExportTimeout))
+		}
+		return otlploggrpc.New(ctx, opts...)
+	case ProtocolHTTP:
+		var opts []otlploghttp.Option
+		if !endpointFromEnv(signal) {
+			opts = append(opts, otlploghttp.WithEndpoint(cfg.OTLPEndpoint))
+			if cfg.Insecure {
+				opts = append(opts, otlploghttp.WithInsecure())
+			}
+		}
+		if len(cfg.Headers) > 0 && !headersFromEnv(signal) {
+			opts = append(opts, otlploghttp.WithHeaders(cfg.Headers))
+		}
+		opts = append(opts, otlploghttp.WithRetry(otlploghttp.RetryConfig(newRetryPolicy(cfg))))
+		if !timeoutFromEnv(signal) {
+			opts = append(opts, otlploghttp.WithTimeout(cfg.ExportTimeout))
+		}
+		return otlploghttp.New(ctx, opts...)
+	default:
+		return nil, fmt.Errorf("unsupported OTLP protocol %q", protocol)
+	}
+}
+
+// newWriterExporters writes telemetry as JSON to w, indented if pretty.
+func newWriterExporters(cfg Config, w io.Writer, pretty bool) (*exporters, error) {
+	traceOpts := []stdouttrace.Option{stdouttrace.WithWriter(w)}
+	metricOpts := []stdoutmetric.Option{stdoutmetric.WithWriter(w)}
+	logOpts := []stdoutlog.Option{stdoutlog.WithWriter(w)}
+	if pretty {
+		traceOpts = append(traceOpts, stdouttrace.WithPrettyPrint())
+		metricOpts = append(metricOpts, stdoutmetric.WithPrettyPrint())
+		logOpts = append(logOpts, stdoutlog.WithPrettyPrint())
+	}
+
+	spanExp, err := stdouttrace.New(traceOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout exporter: %w", err)
+	}
+	metricExp, err := stdoutmetric.New(metricOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout metric exporter: %w", err)
+	}
+
+	exp := &exporters{span: spanExp, metric: metricExp}
+	if cfg.Logs {
+		exp.log, err = stdoutlog.New(logOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout log exporter: %w", err)
+		}
+	}
+	return exp, nil
+}
+
+// endpointFromEnv reports whether the OTLP endpoint for signal is set in
+// the environment, in which case the exporter reads it, together with the
+// matching insecure setting, instead of Config.
+func endpointFromEnv(signal string) bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_"+signal+"_ENDPOINT") != ""
+}
+
+// protocolFor returns the OTLP protocol of signal: the signal's own
+// environment variable, then the general one, then Config.Protocol.
+func protocolFor(signal string, cfg Config) string {
+	return cmp.Or(
+		os.Getenv("OTEL_EXPORTER_OTLP_"+signal+"_PROTOCOL"),
+		os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"),
+		cfg.Protocol,
+	)
+}
+
+// headersFromEnv reports whether OTLP headers for signal are set in the
+// environment, in which case the exporter reads them instead of Config.
+func headersFromEnv(signal string) bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_HEADERS") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_"+signal+"_HEADERS") != ""
+}
+
+// timeoutFromEnv reports whether the OTLP export timeout for signal is set
+// in the environment, in which case it is used instead of Config.
+func timeoutFromEnv(signal string) bool {
+	return os.Getenv("OTEL_EXPORTER_OTLP_TIMEOUT") != "" ||
+		os.Getenv("OTEL_EXPORTER_OTLP_"+signal+"_TIMEOUT") != ""
+}
+
+// retryPolicy has the layout of the RetryConfig of each OTLP exporter
+// package, so that one policy converts to all of them.
+type retryPolicy struct {
+	Enabled         bool
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	MaxElapsedTime  time.Duration
+}
+
+// newRetryPolicy retries failed exports with exponential backoff for up to
+// cfg.RetryMaxElapsed, after which the data is dropped. Exporters never
+// block startup, so the application runs while the collector is down.
+func newRetryPolicy(cfg Config) retryPolicy {
+	return retryPolicy{
+		Enabled:         cfg.RetryMaxElapsed > 0,
+		InitialInterval: 5 * time.Second,
+		MaxInterval:     30 * time.Second,
+		MaxElapsedTime:  cfg.RetryMaxElapsed,
+	}
+}
+
+// sdkDisabled reports whether OTEL_SDK_DISABLED turns telemetry off.
+func sdkDisabled() bool {
+	return strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true")
+}
+
+// newSampler samples the configured ratio of new traces and follows the
+// parent's decision for the rest. It is not used if OTEL_TRACES_SAMPLER
+// is set, as the SDK then configures the sampler from the environment.
+func newSampler(cfg Config) sdktrace.Sampler {
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))
+}
+
+// newResource describes the service. OTEL_SERVICE_NAME and
+// OTEL_RESOURCE_ATTRIBUTES override the configured attributes.
+func newResource(ctx context.Context, cfg Config) (*resource.Resource, error) {
+	attrs := []attribute.KeyValue{semconv.ServiceName(cfg.ServiceName)}
+	if cfg.ServiceVersion != "" {
+		attrs = append(attrs, semconv.ServiceVersion(cfg.ServiceVersion))
+	}
+	if cfg.Environment != "" {
+		attrs = append(attrs, semconv.DeploymentEnvironment(cfg.Environment))
+	}
+	for k, v := range cfg.ResourceAttributes {
+		attrs = append(attrs, attribute.String(k, v))
+	}
+
+	// Create resource without merging to avoid schema URL conflicts
+	return resource.New(ctx,
+		resource.WithAttributes(attrs...),
+		resource.WithHost(),
+		resource.WithProcess(),
+		resource.WithTelemetrySDK(),
+		resource.WithFromEnv(),
+	)
+}
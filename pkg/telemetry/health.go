@@ -0,0 +1,19 @@
+package telemetry
+
+import (
+	"context"
+
+	"project_template/pkg/health"
+)
+
+// NewHealthChecker reports whether pending spans can be flushed to the
+// exporter. It is informational: a missing collector must not take the
+// instance out of rotation.
+func NewHealthChecker(t *Telemetry) health.Checker {
+	return health.NewCheckerFunc("telemetry", health.Informational, func(ctx context.Context) error {
+		if t.TracerProvider == nil {
+			return nil
+		}
+		return t.TracerProvider.ForceFlush(ctx)
+	})
+}
@@ -5,6 +5,8 @@ import (
 	"net/http"
 	"time"
 
+	"project_template/pkg/webserver"
+
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -76,9 +78,10 @@ func HTTPMetricsMiddleware(next http.Handler) http.Handler {
 		ctx := r.Context()
 		start := time.Now()
 
+		// The route is not known until the router has matched the request,
+		// so active requests are tracked by method only.
 		attrs := []attribute.KeyValue{
 			attribute.String("http.method", r.Method),
-			attribute.String("http.route", r.URL.Path),
 		}
 
 		// Track active requests
@@ -92,7 +95,10 @@ func HTTPMetricsMiddleware(next http.Handler) http.Handler {
 
 		// Record metrics after request completes
 		duration := time.Since(start).Seconds()
-		statusAttrs := append(attrs, attribute.Int("http.status_code", rw.statusCode))
+		statusAttrs := append(attrs,
+			attribute.String("http.route", webserver.RouteTemplate(r)),
+			attribute.Int("http.status_code", rw.statusCode),
+		)
 
 		metrics.requestCounter.Add(ctx, 1, metric.WithAttributes(statusAttrs...))
 		metrics.requestDuration.Record(ctx, duration, metric.WithAttributes(statusAttrs...))
@@ -158,39 +164,3 @@ func (m *DatabaseMetrics) RecordQuery(ctx context.Context, operation string, dur
 	m.queryCounter.Add(ctx, 1, metric.WithAttributes(attrs...))
 	m.queryDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
 }
-
-// BusinessMetrics holds business-related metrics instruments
-type BusinessMetrics struct {
-	meter metric.Meter
-}
-
-// NewBusinessMetrics creates business metrics instruments
-func NewBusinessMetrics(meter metric.Meter) *BusinessMetrics {
-	return &BusinessMetrics{meter: meter}
-}
-
-// RecordUserCreated records a user creation event
-func (m *BusinessMetrics) RecordUserCreated(ctx context.Context) {
-	counter, err := m.meter.Int64Counter(
-		"app.users.created.total",
-		metric.WithDescription("Total number of users created"),
-		metric.WithUnit("{user}"),
-	)
-	if err != nil {
-		return
-	}
-	counter.Add(ctx, 1)
-}
-
-// RecordUserFetched records a user fetch event
-func (m *BusinessMetrics) RecordUserFetched(ctx context.Context) {
-	counter, err := m.meter.Int64Counter(
-		"app.users.fetched.total",
-		metric.WithDescription("Total number of user fetch operations"),
-		metric.WithUnit("{operation}"),
-	)
-	if err != nil {
-		return
-	}
-	counter.Add(ctx, 1)
-}
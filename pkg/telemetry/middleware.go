@@ -2,6 +2,7 @@ package telemetry
 
 import (
 	"net/http"
+	"project_template/pkg/requestid"
 	"project_template/pkg/webserver"
 
 	"go.opentelemetry.io/otel"
@@ -10,16 +11,29 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
-// NewHTTPMiddleware returns the tracing middleware as a provider for fx injection.
-func NewHTTPMiddleware(_ *Telemetry) webserver.Middleware {
+// NewHTTPMiddleware returns the tracing middleware as a provider for fx
+// injection. It leaves requests untouched if traces are not exported.
+func NewHTTPMiddleware(t *Telemetry) webserver.Middleware {
+	if t.TracerProvider == nil {
+		return passthrough
+	}
 	return HTTPMiddleware
 }
 
-// NewHTTPMetricsMiddleware returns the metrics middleware as a provider for fx injection.
-func NewHTTPMetricsMiddleware(_ *Telemetry) webserver.Middleware {
+// NewHTTPMetricsMiddleware returns the metrics middleware as a provider for
+// fx injection. It leaves requests untouched if metrics are neither
+// exported nor scraped.
+func NewHTTPMetricsMiddleware(t *Telemetry) webserver.Middleware {
+	if t.MeterProvider == nil {
+		return passthrough
+	}
 	return HTTPMetricsMiddleware
 }
 
+func passthrough(next http.Handler) http.Handler {
+	return next
+}
+
 // HTTPMiddleware creates a middleware that traces HTTP requests
 func HTTPMiddleware(next http.Handler) http.Handler {
 	tracer := otel.Tracer("http-server")
@@ -28,9 +42,9 @@ func HTTPMiddleware(next http.Handler) http.Handler {
 		// Extract trace context from incoming request
 		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
 
-		// Start a new span
-		spanName := r.Method + " " + r.URL.Path
-		ctx, span := tracer.Start(ctx, spanName,
+		// Start a new span. It is named after the route once the router
+		// has matched one; raw paths would make a span name per resource.
+		ctx, span := tracer.Start(ctx, r.Method,
 			trace.WithSpanKind(trace.SpanKindServer),
 			trace.WithAttributes(
 				attribute.String("http.request.method", r.Method),
@@ -43,14 +57,25 @@ func HTTPMiddleware(next http.Handler) http.Handler {
 		)
 		defer span.End()
 
+		if id := requestid.FromContext(ctx); id != "" {
+			span.SetAttributes(attribute.String("http.request.id", id))
+		}
+
 		// Wrap response writer to capture status code
 		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
 
 		// Call the next handler with the traced context
 		next.ServeHTTP(rw, r.WithContext(ctx))
 
-		// Record response attributes
-		span.SetAttributes(attribute.Int("http.response.status_code", rw.statusCode))
+		// Record route and response attributes
+		route := webserver.RouteTemplate(r)
+		if route != webserver.UnmatchedRoute {
+			span.SetName(r.Method + " " + route)
+		}
+		span.SetAttributes(
+			attribute.String("http.route", route),
+			attribute.Int("http.response.status_code", rw.statusCode),
+		)
 
 		// Mark span as error if status code indicates failure
 		if rw.statusCode >= 400 {
@@ -1,7 +1,17 @@
 package telemetry
 
-import "go.uber.org/fx"
+import (
+	"project_template/pkg/health"
+	"project_template/pkg/logger"
+
+	"go.uber.org/fx"
+)
 
 var Module = fx.Module("telemetry",
+	logger.ForModule("telemetry"),
 	fx.Provide(NewTelemetry),
+	fx.Provide(NewRegistry),
+	fx.Provide(health.AsChecker(NewHealthChecker)),
+	fx.Provide(fx.Annotate(NewMetricsRoutes, fx.ResultTags(`group:"routes,flatten"`))),
+	fx.Invoke(startMetricsServer),
 )
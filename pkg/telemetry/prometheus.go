@@ -0,0 +1,119 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"project_template/pkg/shutdown"
+	"project_template/pkg/webserver"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.uber.org/fx"
+)
+
+type PrometheusConfig struct {
+	Enabled bool `mapstructure:"enabled" default:"false"`
+	// Address serves the endpoint on a separate listener, e.g. ":9464",
+	// instead of the webserver, to keep metrics off the public port.
+	Address string `mapstructure:"address"`
+	Path    string `mapstructure:"path" default:"/metrics"`
+}
+
+// newPrometheus creates a metric reader for Prometheus and the handler
+// serving its registry, which also holds Go runtime and process metrics.
+func newPrometheus() (sdkmetric.Reader, http.Handler, error) {
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collectors.NewGoCollector()); err != nil {
+		return nil, nil, err
+	}
+	if err := registry.Register(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{})); err != nil {
+		return nil, nil, err
+	}
+
+	reader, err := otelprom.New(otelprom.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, err
+	}
+	return reader, promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry}), nil
+}
+
+// MetricsHandler serves the Prometheus endpoint on the webserver.
+type MetricsHandler struct {
+	path    string
+	handler http.Handler
+}
+
+func (h *MetricsHandler) Pattern() string {
+	return "GET " + h.path
+}
+
+// RequiresAuth is false: scrapers do not send credentials, and a separate
+// metrics address is the way to keep the endpoint private.
+func (*MetricsHandler) RequiresAuth() bool {
+	return false
+}
+
+// RateLimit is disabled so that scrapes do not consume the buckets of the
+// API traffic sharing their address.
+func (*MetricsHandler) RateLimit() webserver.RateLimit {
+	return webserver.RateLimit{Disabled: true}
+}
+
+func (h *MetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.handler.ServeHTTP(w, r)
+}
+
+// NewMetricsRoutes returns the Prometheus route if metrics are served by
+// the webserver, and no route otherwise.
+func NewMetricsRoutes(t *Telemetry) []webserver.Route {
+	cfg := t.config.Prometheus
+	if t.metricsHandler == nil || cfg.Address != "" {
+		return nil
+	}
+	return []webserver.Route{&MetricsHandler{path: cfg.Path, handler: t.metricsHandler}}
+}
+
+// startMetricsServer serves the Prometheus endpoint on its own address if
+// one is configured. It is stopped with telemetry so that the final
+// values can still be scraped while the application drains.
+func startMetricsServer(lc fx.Lifecycle, t *Telemetry, logger *slog.Logger, coordinator *shutdown.Coordinator) {
+	cfg := t.config.Prometheus
+	if t.metricsHandler == nil || cfg.Address == "" {
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("GET "+cfg.Path, t.metricsHandler)
+	srv := &http.Server{
+		Addr:              cfg.Address,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	lc.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			ln, err := net.Listen("tcp", cfg.Address)
+			if err != nil {
+				return fmt.Errorf("failed to listen for metrics: %w", err)
+			}
+			logger.Info("Starting metrics server", "address", ln.Addr().String(), "path", cfg.Path)
+			go func() {
+				if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+					logger.Error("metrics server error", "error", err)
+				}
+			}()
+			return nil
+		},
+	})
+
+	coordinator.Register(shutdown.PhaseTelemetry, "metrics server", srv.Shutdown)
+}
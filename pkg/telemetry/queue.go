@@ -0,0 +1,75 @@
+package telemetry
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// spanQueue bounds the spans waiting for export and counts the spans that
+// are dropped, either because the queue is full or because their export
+// failed after retries. The batch processor drops spans silently; keeping
+// the count of pending spans here, below its own queue size, makes every
+// drop visible as telemetry.spans.dropped.
+type spanQueue struct {
+	sdktrace.SpanProcessor
+	limit   int64
+	pending atomic.Int64
+	dropped metric.Int64Counter
+}
+
+func newSpanQueue(exporter sdktrace.SpanExporter, size int, meter metric.Meter) (*spanQueue, error) {
+	dropped, err := meter.Int64Counter(
+		"telemetry.spans.dropped",
+		metric.WithDescription("Spans dropped before reaching the collector"),
+		metric.WithUnit("{span}"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if size <= 0 {
+		size = sdktrace.DefaultMaxQueueSize
+	}
+	q := &spanQueue{limit: int64(size), dropped: dropped}
+	q.SpanProcessor = sdktrace.NewBatchSpanProcessor(
+		&queuedExporter{SpanExporter: exporter, queue: q},
+		sdktrace.WithMaxQueueSize(size),
+	)
+	return q, nil
+}
+
+func (q *spanQueue) OnEnd(s sdktrace.ReadOnlySpan) {
+	// The batch processor ignores unsampled spans.
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	if q.pending.Add(1) > q.limit {
+		q.pending.Add(-1)
+		q.drop(1, "queue_full")
+		return
+	}
+	q.SpanProcessor.OnEnd(s)
+}
+
+func (q *spanQueue) drop(n int, reason string) {
+	q.dropped.Add(context.Background(), int64(n), metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+// queuedExporter releases exported spans from the queue.
+type queuedExporter struct {
+	sdktrace.SpanExporter
+	queue *spanQueue
+}
+
+func (e *queuedExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	e.queue.pending.Add(-int64(len(spans)))
+	if err != nil {
+		e.queue.drop(len(spans), "export_failed")
+	}
+	return err
+}
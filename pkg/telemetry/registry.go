@@ -0,0 +1,129 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Registry creates the application's business metrics. Bounded contexts
+// declare their instruments once at startup, typically in a constructor
+// provided through fx, and inject the returned handles into services:
+//
+//	type UserMetrics struct {
+//		Created *telemetry.Counter
+//	}
+//
+//	func NewUserMetrics(r *telemetry.Registry) (*UserMetrics, error) {
+//		created, err := r.Counter("app.users.created.total", "Total number of users created", "{user}")
+//		...
+//	}
+//
+// Declaring the same name twice returns the same handle, so contexts can
+// share an instrument; declaring it with another type is an error.
+type Registry struct {
+	meter metric.Meter
+
+	mu          sync.Mutex
+	instruments map[string]any
+}
+
+func NewRegistry(t *Telemetry) *Registry {
+	return &Registry{
+		meter:       t.Meter,
+		instruments: make(map[string]any),
+	}
+}
+
+// Counter is a monotonically increasing count, such as users created.
+type Counter struct {
+	counter metric.Int64Counter
+}
+
+// Add increments the counter by n.
+func (c *Counter) Add(ctx context.Context, n int64, attrs ...attribute.KeyValue) {
+	c.counter.Add(ctx, n, metric.WithAttributes(attrs...))
+}
+
+// Inc increments the counter by one.
+func (c *Counter) Inc(ctx context.Context, attrs ...attribute.KeyValue) {
+	c.Add(ctx, 1, attrs...)
+}
+
+// Histogram records a distribution of values, such as order amounts.
+type Histogram struct {
+	histogram metric.Float64Histogram
+}
+
+func (h *Histogram) Record(ctx context.Context, v float64, attrs ...attribute.KeyValue) {
+	h.histogram.Record(ctx, v, metric.WithAttributes(attrs...))
+}
+
+// Gauge reports the current value of something, such as a queue length.
+type Gauge struct {
+	gauge metric.Int64Gauge
+}
+
+func (g *Gauge) Set(ctx context.Context, v int64, attrs ...attribute.KeyValue) {
+	g.gauge.Record(ctx, v, metric.WithAttributes(attrs...))
+}
+
+// Counter declares a counter.
+func (r *Registry) Counter(name, description, unit string) (*Counter, error) {
+	return declare(r, name, func() (*Counter, error) {
+		c, err := r.meter.Int64Counter(name, metric.WithDescription(description), metric.WithUnit(unit))
+		if err != nil {
+			return nil, err
+		}
+		return &Counter{counter: c}, nil
+	})
+}
+
+// Histogram declares a histogram. If no buckets are given, the SDK's
+// default boundaries are used.
+func (r *Registry) Histogram(name, description, unit string, buckets ...float64) (*Histogram, error) {
+	return declare(r, name, func() (*Histogram, error) {
+		opts := []metric.Float64HistogramOption{metric.WithDescription(description), metric.WithUnit(unit)}
+		if len(buckets) > 0 {
+			opts = append(opts, metric.WithExplicitBucketBoundaries(buckets...))
+		}
+		h, err := r.meter.Float64Histogram(name, opts...)
+		if err != nil {
+			return nil, err
+		}
+		return &Histogram{histogram: h}, nil
+	})
+}
+
+// Gauge declares a gauge.
+func (r *Registry) Gauge(name, description, unit string) (*Gauge, error) {
+	return declare(r, name, func() (*Gauge, error) {
+		g, err := r.meter.Int64Gauge(name, metric.WithDescription(description), metric.WithUnit(unit))
+		if err != nil {
+			return nil, err
+		}
+		return &Gauge{gauge: g}, nil
+	})
+}
+
+func declare[T any](r *Registry, name string, create func() (*T, error)) (*T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if existing, ok := r.instruments[name]; ok {
+		if h, ok := existing.(*T); ok {
+			return h, nil
+		}
+		return nil, fmt.Errorf("metric %q is already declared as %T", name, existing)
+	}
+
+	h, err := create()
+	if err != nil {
+		return nil, fmt.Errorf("failed to declare metric %q: %w", name, err)
+	}
+	r.instruments[name] = h
+	return h, nil
+}
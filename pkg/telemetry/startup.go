@@ -0,0 +1,111 @@
+package telemetry
+
+import (
+	"cmp"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
+)
+
+// effectiveConfig describes the telemetry setup as it is in effect, with
+// OTEL_* environment variables applied, for the startup log.
+func effectiveConfig(cfg Config, res *resource.Resource, push bool) []any {
+	service, _ := res.Set().Value(semconv.ServiceNameKey)
+	args := []any{
+		"service", service.AsString(),
+		"push", push,
+		"prometheus", cfg.Prometheus.Enabled,
+	}
+	if cfg.Prometheus.Enabled {
+		args = append(args, "prometheus_endpoint", cfg.Prometheus.Address+cfg.Prometheus.Path)
+	}
+	if !push {
+		return args
+	}
+
+	exporter := cmp.Or(cfg.Exporter, ExporterOTLP)
+	args = append(args, "exporter", exporter)
+	switch exporter {
+	case ExporterOTLP:
+		args = append(args,
+			"protocol", otlpProtocol(cfg),
+			"endpoint", otlpEndpoint(cfg),
+			"export_timeout", millisFromEnv("OTEL_EXPORTER_OTLP_TIMEOUT", cfg.ExportTimeout),
+			"retry_max_elapsed", cfg.RetryMaxElapsed,
+		)
+	case ExporterFile:
+		args = append(args, "file_path", cfg.FilePath)
+	}
+
+	sampler := "parentbased_traceidratio"
+	ratio := cfg.SampleRatio
+	if env := os.Getenv("OTEL_TRACES_SAMPLER"); env != "" {
+		sampler = env
+		ratio = -1
+	}
+	args = append(args, "sampler", sampler)
+	if ratio >= 0 {
+		args = append(args, "sample_ratio", ratio)
+	}
+
+	return append(args,
+		"metric_interval", millisFromEnv("OTEL_METRIC_EXPORT_INTERVAL", cfg.MetricInterval),
+		"max_queue_size", cfg.MaxQueueSize,
+		"logs", cfg.Logs,
+	)
+}
+
+// millisFromEnv returns the duration in milliseconds set in the
+// environment variable key, or fallback.
+func millisFromEnv(key string, fallback time.Duration) time.Duration {
+	if ms, err := strconv.Atoi(os.Getenv(key)); err == nil {
+		return time.Duration(ms) * time.Millisecond
+	}
+	return fallback
+}
+
+func otlpProtocol(cfg Config) string {
+	return cmp.Or(os.Getenv("OTEL_EXPORTER_OTLP_PROTOCOL"), cfg.Protocol)
+}
+
+// otlpEndpoint returns the endpoint traces are exported to.
+func otlpEndpoint(cfg Config) string {
+	return cmp.Or(
+		os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"),
+		os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		cfg.OTLPEndpoint,
+	)
+}
+
+// probeCollector checks once whether the collector accepts connections
+// and warns if it does not. Startup does not wait for it: telemetry is
+// queued and retried until the collector is reachable.
+func probeCollector(cfg Config, logger *slog.Logger) {
+	endpoint := otlpEndpoint(cfg)
+	address := endpoint
+	if u, err := url.Parse(endpoint); err == nil && u.Host != "" {
+		address = u.Host
+	}
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		port := "4317"
+		if strings.HasPrefix(otlpProtocol(cfg), "http") {
+			port = "4318"
+		}
+		address = net.JoinHostPort(address, port)
+	}
+
+	conn, err := net.DialTimeout("tcp", address, 3*time.Second)
+	if err != nil {
+		logger.Warn("Telemetry collector is unreachable; exports are queued and retried",
+			"endpoint", endpoint, "error", err)
+		return
+	}
+	conn.Close()
+}
@@ -1,37 +1,48 @@
 package telemetry
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
-	"time"
+	"net/http"
+	"os"
 
+	"project_template/pkg/logger"
+	"project_template/pkg/shutdown"
+
+	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/log/global"
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/propagation"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
-	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
-	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
 	"go.opentelemetry.io/otel/trace"
-	"go.uber.org/fx"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
 )
 
 type Telemetry struct {
 	TracerProvider *sdktrace.TracerProvider
 	MeterProvider  *sdkmetric.MeterProvider
+	LoggerProvider *sdklog.LoggerProvider
 	Tracer         trace.Tracer
 	Meter          metric.Meter
 	config         Config
+	// metricsHandler serves the Prometheus endpoint if it is enabled.
+	metricsHandler http.Handler
 	logger         *slog.Logger
 }
 
-func NewTelemetry(lc fx.Lifecycle, cfg Config, logger *slog.Logger) (*Telemetry, error) {
-	if !cfg.Enabled {
+// NewTelemetry sets up trace and metric export and, if cfg.Logs is set,
+// attaches an OpenTelemetry log bridge to logExporter so that application
+// logs are exported through the same collector. If cfg.Prometheus is
+// enabled, metrics are also exposed for scraping, even when push export
+// is disabled.
+func NewTelemetry(cfg Config, logger *slog.Logger, coordinator *shutdown.Coordinator, logExporter *logger.Exporter) (*Telemetry, error) {
+	push := cfg.Enabled && !sdkDisabled()
+	if !push && !cfg.Prometheus.Enabled {
 		logger.Info("Telemetry disabled")
 		return &Telemetry{
 			Tracer: otel.Tracer(cfg.ServiceName),
@@ -41,92 +52,114 @@ func NewTelemetry(lc fx.Lifecycle, cfg Config, logger *slog.Logger) (*Telemetry,
 		}, nil
 	}
 
-	logger.Info("Telemetry enabled")
-
 	ctx := context.Background()
 
-	// Create OTLP exporter
-	var opts []otlptracegrpc.Option
-	opts = append(opts, otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint))
-	if cfg.Insecure {
-		opts = append(opts, otlptracegrpc.WithDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())))
-		opts = append(opts, otlptracegrpc.WithInsecure())
-	}
-
-	exporter, err := otlptracegrpc.New(ctx, opts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
-	}
-
-	// Create resource without merging to avoid schema URL conflicts
-	res, err := resource.New(ctx,
-		resource.WithAttributes(
-			semconv.ServiceName(cfg.ServiceName),
-		),
-		resource.WithHost(),
-		resource.WithProcess(),
-		resource.WithTelemetrySDK(),
-	)
+	res, err := newResource(ctx, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
-	// Create TracerProvider
-	tp := sdktrace.NewTracerProvider(
-		sdktrace.WithBatcher(exporter),
-		sdktrace.WithResource(res),
-		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-	)
-
-	// Create metrics exporter
-	var metricOpts []otlpmetricgrpc.Option
-	metricOpts = append(metricOpts, otlpmetricgrpc.WithEndpoint(cfg.OTLPEndpoint))
-	if cfg.Insecure {
-		metricOpts = append(metricOpts, otlpmetricgrpc.WithDialOption(grpc.WithTransportCredentials(insecure.NewCredentials())))
-		metricOpts = append(metricOpts, otlpmetricgrpc.WithInsecure())
+	t := &Telemetry{
+		config: cfg,
+		logger: logger,
 	}
 
-	metricExporter, err := otlpmetricgrpc.New(ctx, metricOpts...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create OTLP metric exporter: %w", err)
+	// Create MeterProvider with a reader per metrics destination
+	mpOpts := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	if cfg.Prometheus.Enabled {
+		reader, handler, err := newPrometheus()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Prometheus exporter: %w", err)
+		}
+		mpOpts = append(mpOpts, sdkmetric.WithReader(reader))
+		t.metricsHandler = handler
 	}
 
-	// Create MeterProvider
-	mp := sdkmetric.NewMeterProvider(
-		sdkmetric.WithResource(res),
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter,
-			sdkmetric.WithInterval(15*time.Second),
-		)),
-	)
-
-	// Set global TracerProvider, MeterProvider and propagator
-	otel.SetTracerProvider(tp)
-	otel.SetMeterProvider(mp)
-	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
-		propagation.TraceContext{},
-		propagation.Baggage{},
-	))
+	var exp *exporters
+	if push {
+		exp, err = newExporters(ctx, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		var readerOpts []sdkmetric.PeriodicReaderOption
+		if os.Getenv("OTEL_METRIC_EXPORT_INTERVAL") == "" {
+			readerOpts = append(readerOpts, sdkmetric.WithInterval(cfg.MetricInterval))
+		}
+		mpOpts = append(mpOpts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exp.metric, readerOpts...)))
+	}
 
-	t := &Telemetry{
-		TracerProvider: tp,
-		MeterProvider:  mp,
-		Tracer:         tp.Tracer(cfg.ServiceName),
-		Meter:          mp.Meter(cfg.ServiceName),
-		config:         cfg,
-		logger:         logger,
+	t.MeterProvider = sdkmetric.NewMeterProvider(mpOpts...)
+	t.Meter = t.MeterProvider.Meter(cfg.ServiceName)
+	otel.SetMeterProvider(t.MeterProvider)
+
+	if push {
+		// Create TracerProvider. Spans are queued in memory, up to
+		// cfg.MaxQueueSize, while the collector is unreachable.
+		queue, err := newSpanQueue(exp.span, cfg.MaxQueueSize, t.Meter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create span queue: %w", err)
+		}
+		tpOpts := []sdktrace.TracerProviderOption{
+			sdktrace.WithSpanProcessor(queue),
+			sdktrace.WithResource(res),
+		}
+		if os.Getenv("OTEL_TRACES_SAMPLER") == "" {
+			tpOpts = append(tpOpts, sdktrace.WithSampler(newSampler(cfg)))
+		}
+		t.TracerProvider = sdktrace.NewTracerProvider(tpOpts...)
+		t.Tracer = t.TracerProvider.Tracer(cfg.ServiceName)
+
+		// Create LoggerProvider and bridge slog records to it. The bridge
+		// takes trace and span IDs from the record's context.
+		if exp.log != nil {
+			t.LoggerProvider = sdklog.NewLoggerProvider(
+				sdklog.WithResource(res),
+				sdklog.WithProcessor(sdklog.NewBatchProcessor(exp.log, sdklog.WithMaxQueueSize(cfg.MaxQueueSize))),
+			)
+			global.SetLoggerProvider(t.LoggerProvider)
+			logExporter.Attach(otelslog.NewHandler(cfg.ServiceName, otelslog.WithLoggerProvider(t.LoggerProvider)))
+		}
+
+		// Set global TracerProvider and propagator
+		otel.SetTracerProvider(t.TracerProvider)
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+			propagation.TraceContext{},
+			propagation.Baggage{},
+		))
+		// Export failures are reported by the SDK; route them through the
+		// application logger instead of the standard library log.
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			logger.Warn("Telemetry export error", "error", err)
+		}))
+		if cmp.Or(cfg.Exporter, ExporterOTLP) == ExporterOTLP {
+			go probeCollector(cfg, logger)
+		}
+	} else {
+		t.Tracer = otel.Tracer(cfg.ServiceName)
 	}
 
-	lc.Append(fx.Hook{
-		OnStop: func(ctx context.Context) error {
-			logger.Info("Shutting down telemetry")
-			if err := mp.Shutdown(ctx); err != nil {
-				logger.Error("Failed to shutdown MeterProvider", "error", err)
-			}
-			return tp.Shutdown(ctx)
-		},
+	coordinator.Register(shutdown.PhaseTelemetry, "telemetry", func(ctx context.Context) error {
+		logger.Info("Shutting down telemetry")
+		if err := t.MeterProvider.Shutdown(ctx); err != nil {
+			logger.Error("Failed to shutdown MeterProvider", "error", err)
+		}
+		if !push {
+			return nil
+		}
+		err := t.TracerProvider.Shutdown(ctx)
+		// Logs go last so that the records above are still exported.
+		if t.LoggerProvider != nil {
+			logExporter.Attach(nil)
+			err = errors.Join(err, t.LoggerProvider.Shutdown(ctx))
+		}
+		if exp.closer != nil {
+			err = errors.Join(err, exp.closer.Close())
+		}
+		return err
 	})
 
-	logger.Info("Telemetry initialized", "endpoint", cfg.OTLPEndpoint, "service", cfg.ServiceName)
+	logger.Info("Telemetry initialized", effectiveConfig(cfg, res, push)...)
 	return t, nil
 }
 
@@ -3,6 +3,40 @@ package webserver
 import "time"
 
 type Config struct {
-	Port              int           `default:"8080"`
+	Port int `default:"8080"`
+	// Address overrides Port. It is either a TCP address such as
+	// "127.0.0.1:8080" or a unix socket path prefixed with "unix:".
+	Address string
+
 	ReadHeaderTimeout time.Duration `default:"10s"`
+	ReadTimeout       time.Duration `default:"30s"`
+	WriteTimeout      time.Duration `default:"30s"`
+	IdleTimeout       time.Duration `default:"120s"`
+	MaxHeaderBytes    int           `default:"1048576"`
+
+	// H2C enables HTTP/2 without TLS (prior knowledge), typically behind
+	// a proxy that terminates TLS. With TLS enabled HTTP/2 is negotiated
+	// automatically.
+	H2C bool
+
+	TLS TLSConfig
+
+	RateLimit RateLimitConfig `mapstructure:"ratelimit"`
+}
+
+type TLSConfig struct {
+	Enabled  bool
+	CertFile string
+	KeyFile  string
+	// ReloadInterval is how often the certificate files are checked for
+	// changes, so rotated certificates are picked up without a restart.
+	ReloadInterval time.Duration `default:"1m"`
+
+	// ClientCAFile enables mutual TLS: client certificates are verified
+	// against the CA bundle in this file.
+	ClientCAFile string
+	// ClientAuth is one of "request", "require", "verify_if_given" or
+	// "require_and_verify". Defaults to "require_and_verify" when
+	// ClientCAFile is set.
+	ClientAuth string
 }
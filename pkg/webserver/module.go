@@ -3,12 +3,17 @@ package webserver
 import (
 	"net/http"
 
+	"project_template/pkg/logger"
+
 	"go.uber.org/fx"
 )
 
 var Module = fx.Module("webserver",
+	logger.ForModule("webserver"),
 	fx.Provide(
 		NewRouter,
+		NewRateLimiter,
+		AsRateLimitStore(NewPostgresRateLimitStore),
 		NewHTTPServer,
 	),
 	fx.Invoke(func(*http.Server) {}),
@@ -0,0 +1,59 @@
+package webserver
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// UnmatchedRoute is the route reported for requests that matched no
+// registered pattern, so that scans of random URLs share one label.
+const UnmatchedRoute = "unmatched"
+
+type routeInfoKey struct{}
+
+// routeInfo carries the matched pattern from the mux, which sees a copy
+// of the request, back out to the middlewares wrapping it.
+type routeInfo struct {
+	pattern string
+}
+
+func withRouteInfo(r *http.Request) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), routeInfoKey{}, &routeInfo{}))
+}
+
+// recordPattern notes the pattern the mux matched before calling h.
+func recordPattern(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if info, ok := r.Context().Value(routeInfoKey{}).(*routeInfo); ok {
+			info.pattern = r.Pattern
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// RoutePattern returns the ServeMux pattern that matched r, such as
+// "GET /api/users/{id}", or "" if none did. Middlewares see it only once
+// the router has dispatched the request, that is after calling the next
+// handler.
+func RoutePattern(r *http.Request) string {
+	if info, ok := r.Context().Value(routeInfoKey{}).(*routeInfo); ok {
+		return info.pattern
+	}
+	return r.Pattern
+}
+
+// RouteTemplate returns the path template of the pattern that matched r, such as
+// "/api/users/{id}", for use as the http.route of spans and metrics. It
+// returns UnmatchedRoute if no pattern matched.
+func RouteTemplate(r *http.Request) string {
+	pattern := RoutePattern(r)
+	if pattern == "" {
+		return UnmatchedRoute
+	}
+	// Strip the method and host: [METHOD ][HOST]/[PATH]
+	if i := strings.IndexByte(pattern, '/'); i >= 0 {
+		return pattern[i:]
+	}
+	return pattern
+}
@@ -0,0 +1,106 @@
+package webserver
+
+import (
+	"cmp"
+	"encoding/json/v2"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/pkg/requestid"
+)
+
+// ProblemContentType is the media type of RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// errorResponse is the legacy error representation.
+type errorResponse struct {
+	Code      string `json:"code,omitempty"`
+	Message   string `json:"message"`
+	Details   any    `json:"details,omitempty"`
+	TraceID   string `json:"trace_id,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// writeJSONError renders appErr as problem details if the client asks for
+// application/problem+json and in the legacy shape otherwise. Both carry
+// the trace and request IDs so that users can quote them in support tickets.
+func writeJSONError(w http.ResponseWriter, r *http.Request, appErr *apperrors.AppError) {
+	traceID := ""
+	if sc := trace.SpanContextFromContext(r.Context()); sc.HasTraceID() {
+		traceID = sc.TraceID().String()
+	}
+	requestID := requestid.FromContext(r.Context())
+
+	if !acceptsProblem(r) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(appErr.Code)
+		_ = json.MarshalWrite(w, errorResponse{
+			Code:      appErr.ErrorCode,
+			Message:   appErr.Message,
+			Details:   appErr.Details,
+			TraceID:   traceID,
+			RequestID: requestID,
+		})
+		return
+	}
+
+	// Problem details are built as a map because extension members sit
+	// at the top level next to the standard members.
+	problem := make(map[string]any, len(appErr.Extensions)+8)
+	for k, v := range appErr.Extensions {
+		problem[k] = v
+	}
+	problem["type"] = cmp.Or(appErr.Type, "about:blank")
+	problem["title"] = cmp.Or(appErr.Title, http.StatusText(appErr.Code))
+	problem["status"] = appErr.Code
+	problem["instance"] = cmp.Or(appErr.Instance, r.URL.Path)
+	if appErr.ErrorCode != "" {
+		problem["code"] = appErr.ErrorCode
+	}
+	if appErr.Message != "" {
+		problem["detail"] = appErr.Message
+	}
+	if appErr.Details != nil {
+		problem["details"] = appErr.Details
+	}
+	if traceID != "" {
+		problem["trace_id"] = traceID
+	}
+	if requestID != "" {
+		problem["request_id"] = requestID
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(appErr.Code)
+	_ = json.MarshalWrite(w, problem, json.Deterministic(true))
+}
+
+// acceptsProblem reports whether the Accept header prefers problem details
+// over plain JSON.
+func acceptsProblem(r *http.Request) bool {
+	problemQ, jsonQ := 0.0, 0.0
+	for _, part := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		switch mediaType {
+		case ProblemContentType:
+			problemQ = max(problemQ, q)
+		case "application/json":
+			jsonQ = max(jsonQ, q)
+		}
+	}
+	return problemQ > 0 && problemQ >= jsonQ
+}
@@ -0,0 +1,333 @@
+package webserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	apperrors "project_template/internal/shared/errors"
+	"project_template/pkg/logger"
+
+	"go.uber.org/fx"
+)
+
+// RateLimit is a token bucket: a client may send Burst requests at once
+// and Rate requests per second on average.
+type RateLimit struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+	// Disabled exempts a route from rate limiting.
+	Disabled bool `mapstructure:"disabled"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Store holds the buckets: "memory" keeps them per instance,
+	// "postgres" shares them between instances.
+	Store string `mapstructure:"store" default:"memory"`
+	// Rate and Burst are the limit of routes without an override. These
+	// routes share one bucket per client.
+	Rate  float64 `mapstructure:"rate" default:"10"`
+	Burst int     `mapstructure:"burst" default:"20"`
+	// Routes overrides the limit by route pattern, such as
+	// "POST /api/users". Patterns match case-insensitively, as the config
+	// loader lowercases map keys. Each overridden route has its own bucket.
+	Routes map[string]RateLimit `mapstructure:"routes"`
+	// AuthFailureRate and AuthFailureBurst limit the requests per client
+	// IP that fail authentication with a 401. Once a client is out of
+	// tokens, its requests are rejected before any credentials are checked.
+	AuthFailureRate  float64 `mapstructure:"auth_failure_rate" default:"0.1"`
+	AuthFailureBurst int     `mapstructure:"auth_failure_burst" default:"10"`
+	// TrustForwardedFor takes the client IP from the last X-Forwarded-For
+	// entry, as appended by a single reverse proxy in front of the server.
+	TrustForwardedFor bool `mapstructure:"trust_forwarded_for"`
+}
+
+// RateLimited is implemented by routes that declare their own limit. A
+// limit in RateLimitConfig.Routes takes precedence.
+type RateLimited interface {
+	RateLimit() RateLimit
+}
+
+// RateLimitResult is the state of a bucket after taking a token from it.
+type RateLimitResult struct {
+	Allowed bool
+	// Tokens is what is left in the bucket.
+	Tokens float64
+}
+
+// RateLimitStore keeps token buckets by key.
+type RateLimitStore interface {
+	// Name is the value of RateLimitConfig.Store that selects the store.
+	Name() string
+	// Take takes a token from the bucket key if it holds one, after
+	// refilling it for the time since the last call.
+	Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error)
+	// Refund puts back a token taken from the bucket key.
+	Refund(ctx context.Context, key string, limit RateLimit) error
+}
+
+// AsRateLimitStore annotates the given constructor to state that
+// it provides a rate limit store to the "ratelimitstores" group.
+func AsRateLimitStore(f any) any {
+	return fx.Annotate(
+		f,
+		fx.As(new(RateLimitStore)),
+		fx.ResultTags(`group:"ratelimitstores"`),
+	)
+}
+
+// RateLimiter limits the requests each client sends to a route. Clients
+// are told apart by the authenticated principal or API key, and otherwise
+// by IP address. Responses carry the RateLimit-Limit, RateLimit-Remaining
+// and RateLimit-Reset headers; rejected requests get a 429 with
+// Retry-After. If the store fails, requests are let through.
+type RateLimiter struct {
+	config RateLimitConfig
+	store  RateLimitStore
+	logger *slog.Logger
+}
+
+type RateLimiterParams struct {
+	fx.In
+	Config Config
+	Logger *slog.Logger
+	Stores []RateLimitStore `group:"ratelimitstores"`
+}
+
+func NewRateLimiter(params RateLimiterParams) (*RateLimiter, error) {
+	cfg := params.Config.RateLimit
+	l := &RateLimiter{config: cfg, logger: params.Logger}
+	if !cfg.Enabled {
+		return l, nil
+	}
+
+	for _, s := range append([]RateLimitStore{NewMemoryRateLimitStore()}, params.Stores...) {
+		if s.Name() == cfg.Store {
+			l.store = s
+		}
+	}
+	if l.store == nil {
+		return nil, fmt.Errorf("unknown rate limit store %q", cfg.Store)
+	}
+	for pattern, limit := range cfg.Routes {
+		if err := limit.validate(); err != nil {
+			return nil, fmt.Errorf("invalid rate limit for %q: %w", pattern, err)
+		}
+	}
+	if err := (RateLimit{Rate: cfg.Rate, Burst: cfg.Burst}).validate(); err != nil {
+		return nil, fmt.Errorf("invalid default rate limit: %w", err)
+	}
+	if err := l.authFailureLimit().validate(); err != nil {
+		return nil, fmt.Errorf("invalid authentication failure limit: %w", err)
+	}
+	return l, nil
+}
+
+func (rl RateLimit) validate() error {
+	if rl.Disabled {
+		return nil
+	}
+	if rl.Rate <= 0 || rl.Burst < 1 {
+		return errors.New("rate must be positive and burst at least 1")
+	}
+	return nil
+}
+
+// wrap applies the limit of route to h.
+func (l *RateLimiter) wrap(route any, pattern string, h http.Handler) http.Handler {
+	if !l.config.Enabled {
+		return h
+	}
+	limit, scope := l.limitFor(route, pattern)
+	if limit.Disabled {
+		return h
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		result, err := l.store.Take(ctx, scope+"|"+l.clientKey(r), limit)
+		if err != nil {
+			l.logger.WarnContext(ctx, "rate limit store failed, allowing request", "error", err)
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		header := w.Header()
+		header.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		header.Set("RateLimit-Remaining", strconv.Itoa(int(max(result.Tokens, 0))))
+		header.Set("RateLimit-Reset", strconv.Itoa(seconds(float64(limit.Burst)-result.Tokens, limit.Rate)))
+		if !result.Allowed {
+			header.Set("Retry-After", strconv.Itoa(seconds(1-result.Tokens, limit.Rate)))
+			handleError(w, r, l.logger, apperrors.NewTooManyRequests("rate limit exceeded"))
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// CredentialHeader names a request header that carries credentials, such
+// as an API key. Requests with one, or with a bearer token, count towards
+// the authentication failure limit.
+type CredentialHeader string
+
+// AsCredentialHeader annotates the given constructor to state that
+// it provides a credential header to the "credentialheaders" group.
+func AsCredentialHeader(f any) any {
+	return fx.Annotate(
+		f,
+		fx.ResultTags(`group:"credentialheaders"`),
+	)
+}
+
+type AuthFailureParams struct {
+	fx.In
+	Limiter *RateLimiter
+	Headers []CredentialHeader `group:"credentialheaders"`
+}
+
+// NewAuthFailureMiddleware limits failed authentication by client IP. It
+// must run before the authentication middlewares, so that a client
+// guessing credentials is stopped before they are verified, and so that
+// requests rejected by authentication are limited at all: the route
+// limits apply only once a request reaches the router.
+//
+// Only requests carrying credentials are limited. Each takes a token up
+// front, so concurrent guesses cannot exceed the burst, and gets it back
+// unless it ends in a 401.
+func NewAuthFailureMiddleware(params AuthFailureParams) Middleware {
+	l := params.Limiter
+	if !l.config.Enabled {
+		return func(next http.Handler) http.Handler { return next }
+	}
+	limit := l.authFailureLimit()
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !hasCredentials(r, params.Headers) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			key := "auth_failures|ip:" + l.clientIP(r)
+			result, err := l.store.Take(ctx, key, limit)
+			if err != nil {
+				l.logger.WarnContext(ctx, "rate limit store failed, allowing request", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(seconds(1-result.Tokens, limit.Rate)))
+				handleError(w, r, l.logger, apperrors.NewTooManyRequests("too many failed authentication attempts"))
+				return
+			}
+
+			sw := &statusResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(sw, r)
+			if sw.status != http.StatusUnauthorized {
+				if err := l.store.Refund(context.WithoutCancel(ctx), key, limit); err != nil {
+					l.logger.WarnContext(ctx, "failed to refund authentication attempt", "error", err)
+				}
+			}
+		})
+	}
+}
+
+func hasCredentials(r *http.Request, headers []CredentialHeader) bool {
+	scheme, token, _ := strings.Cut(r.Header.Get("Authorization"), " ")
+	if strings.EqualFold(scheme, "Bearer") && token != "" {
+		return true
+	}
+	for _, h := range headers {
+		if h != "" && r.Header.Get(string(h)) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *RateLimiter) authFailureLimit() RateLimit {
+	return RateLimit{Rate: l.config.AuthFailureRate, Burst: l.config.AuthFailureBurst}
+}
+
+// statusResponseWriter records the status code of the response.
+type statusResponseWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusResponseWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// limitFor returns the limit of a route and the scope of its buckets.
+func (l *RateLimiter) limitFor(route any, pattern string) (RateLimit, string) {
+	for p, limit := range l.config.Routes {
+		if strings.EqualFold(p, pattern) {
+			return limit, pattern
+		}
+	}
+	if r, ok := route.(RateLimited); ok {
+		return r.RateLimit(), pattern
+	}
+	return RateLimit{Rate: l.config.Rate, Burst: l.config.Burst}, "*"
+}
+
+func (l *RateLimiter) clientKey(r *http.Request) string {
+	// The authentication middlewares record the principal, including
+	// API keys, as the user ID.
+	if id := logger.UserID(r.Context()); id != "" {
+		return "user:" + id
+	}
+	return "ip:" + l.clientIP(r)
+}
+
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.config.TrustForwardedFor {
+		if xff := r.Header.Values("X-Forwarded-For"); len(xff) > 0 {
+			entries := strings.Split(xff[len(xff)-1], ",")
+			if ip := strings.TrimSpace(entries[len(entries)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// seconds returns how long refilling tokens takes at rate, rounded up.
+func seconds(tokens, rate float64) int {
+	if tokens <= 0 {
+		return 0
+	}
+	return int(math.Ceil(tokens / rate))
+}
+
+// refill returns the tokens of a bucket that held tokens elapsed ago.
+func refill(tokens float64, elapsed time.Duration, limit RateLimit) float64 {
+	return min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+}
@@ -0,0 +1,84 @@
+package webserver
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRateLimitStore keeps token buckets in memory. Each instance limits
+// on its own, so behind a load balancer clients get the limit once per
+// instance.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*memoryBucket
+	lastSweep time.Time
+}
+
+type memoryBucket struct {
+	tokens  float64
+	updated time.Time
+	limit   RateLimit
+}
+
+// memorySweepInterval is how often full buckets are dropped. A full bucket
+// behaves like a missing one, so dropping it only frees memory.
+const memorySweepInterval = time.Minute
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets:   make(map[string]*memoryBucket),
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *MemoryRateLimitStore) Name() string {
+	return "memory"
+}
+
+func (s *MemoryRateLimitStore) Take(_ context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= memorySweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: float64(limit.Burst), updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens = refill(b.tokens, now.Sub(b.updated), limit)
+	b.updated = now
+	b.limit = limit
+
+	if b.tokens < 1 {
+		return RateLimitResult{Tokens: b.tokens}, nil
+	}
+	b.tokens--
+	return RateLimitResult{Allowed: true, Tokens: b.tokens}, nil
+}
+
+func (s *MemoryRateLimitStore) Refund(_ context.Context, key string, limit RateLimit) error {
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// A bucket swept since the token was taken is already full.
+	if b, ok := s.buckets[key]; ok {
+		b.tokens = min(float64(limit.Burst), refill(b.tokens, now.Sub(b.updated), limit)+1)
+		b.updated = now
+	}
+	return nil
+}
+
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if refill(b.tokens, now.Sub(b.updated), b.limit) >= float64(b.limit.Burst) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,98 @@
+package webserver
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// takeTokenSQL refills the bucket for the time since its last update and
+// takes a token if it then holds one, in a single statement so that
+// concurrent requests from several instances cannot overdraw it.
+const takeTokenSQL = `
+INSERT INTO rate_limit_buckets AS b (key, tokens, allowed, updated_at)
+VALUES (@key, @burst - 1, TRUE, NOW())
+ON CONFLICT (key) DO UPDATE SET
+    tokens = LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at) * @rate)
+        - CASE WHEN LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at) * @rate) >= 1 THEN 1 ELSE 0 END,
+    allowed = LEAST(@burst, b.tokens + EXTRACT(EPOCH FROM NOW() - b.updated_at) * @rate) >= 1,
+    updated_at = NOW()
+RETURNING tokens, allowed`
+
+const refundTokenSQL = `
+UPDATE rate_limit_buckets
+SET tokens = LEAST(@burst, tokens + EXTRACT(EPOCH FROM NOW() - updated_at) * @rate + 1),
+    updated_at = NOW()
+WHERE key = @key`
+
+// PostgresRateLimitStore keeps rate limit buckets in Postgres, so that all
+// instances share them. Buckets idle for longer than the retention are
+// deleted, which refills them early only for limits that take longer
+// than that to refill.
+type PostgresRateLimitStore struct {
+	db        *gorm.DB
+	logger    *slog.Logger
+	retention time.Duration
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+func NewPostgresRateLimitStore(db *gorm.DB, logger *slog.Logger) *PostgresRateLimitStore {
+	return &PostgresRateLimitStore{
+		db:        db,
+		logger:    logger,
+		retention: time.Hour,
+		lastSweep: time.Now(),
+	}
+}
+
+func (s *PostgresRateLimitStore) Name() string {
+	return "postgres"
+}
+
+func (s *PostgresRateLimitStore) Take(ctx context.Context, key string, limit RateLimit) (RateLimitResult, error) {
+	s.sweep(ctx)
+
+	var result RateLimitResult
+	err := s.db.WithContext(ctx).Raw(takeTokenSQL, map[string]any{
+		"key":   key,
+		"burst": limit.Burst,
+		"rate":  limit.Rate,
+	}).Row().Scan(&result.Tokens, &result.Allowed)
+	return result, err
+}
+
+func (s *PostgresRateLimitStore) Refund(ctx context.Context, key string, limit RateLimit) error {
+	return s.db.WithContext(ctx).Exec(refundTokenSQL, map[string]any{
+		"key":   key,
+		"burst": limit.Burst,
+		"rate":  limit.Rate,
+	}).Error
+}
+
+// sweep deletes idle buckets in the background, at most once per minute
+// per instance.
+func (s *PostgresRateLimitStore) sweep(ctx context.Context) {
+	s.mu.Lock()
+	if time.Since(s.lastSweep) < time.Minute {
+		s.mu.Unlock()
+		return
+	}
+	s.lastSweep = time.Now()
+	s.mu.Unlock()
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+		err := s.db.WithContext(ctx).
+			Exec("DELETE FROM rate_limit_buckets WHERE updated_at < ?", time.Now().Add(-s.retention)).Error
+		if err != nil {
+			s.logger.WarnContext(ctx, "failed to delete idle rate limit buckets", "error", err)
+		}
+	}()
+}
@@ -0,0 +1,99 @@
+package webserver
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+
+	apperrors "project_template/internal/shared/errors"
+)
+
+// NewRecoveryMiddleware returns a middleware that turns a panic in a
+// handler into a 500 response with the standard error body. The panic is
+// logged with its stack, recorded on the active span and counted in the
+// http.server.panics metric.
+//
+// The router applies it innermost, so the tracing middleware's span is
+// still active when a panic is recovered.
+func NewRecoveryMiddleware(logger *slog.Logger) Middleware {
+	panics, err := otel.Meter("http-server").Int64Counter(
+		"http.server.panics",
+		metric.WithDescription("Number of panics recovered in HTTP handlers"),
+		metric.WithUnit("{panic}"),
+	)
+	if err != nil {
+		logger.Error("failed to create panic counter", "error", err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rw := &recoveryResponseWriter{ResponseWriter: w}
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				// http.ErrAbortHandler is the sanctioned way to abort a
+				// response; let net/http handle it silently.
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				ctx := r.Context()
+				stack := string(debug.Stack())
+				logger.ErrorContext(ctx, "panic recovered",
+					"panic", fmt.Sprint(rec),
+					"method", r.Method,
+					"path", r.URL.Path,
+					"stack", stack,
+				)
+
+				span := trace.SpanFromContext(ctx)
+				span.RecordError(fmt.Errorf("panic: %v", rec), trace.WithAttributes(
+					attribute.String("exception.stacktrace", stack),
+				))
+				span.SetStatus(codes.Error, "panic")
+
+				if panics != nil {
+					panics.Add(ctx, 1, metric.WithAttributes(
+						attribute.String("http.method", r.Method),
+					))
+				}
+
+				if !rw.wroteHeader {
+					writeJSONError(w, r, apperrors.NewInternalError("internal server error"))
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// recoveryResponseWriter records whether the response has started, in
+// which case an error body can no longer be written.
+type recoveryResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (rw *recoveryResponseWriter) WriteHeader(code int) {
+	rw.wroteHeader = true
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+func (rw *recoveryResponseWriter) Write(b []byte) (int, error) {
+	rw.wroteHeader = true
+	return rw.ResponseWriter.Write(b)
+}
+
+func (rw *recoveryResponseWriter) Unwrap() http.ResponseWriter {
+	return rw.ResponseWriter
+}
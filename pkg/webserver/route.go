@@ -1,7 +1,6 @@
 package webserver
 
 import (
-	"encoding/json/v2"
 	"log/slog"
 	"net/http"
 
@@ -37,9 +36,37 @@ type AppRoute interface {
 	Handle(w http.ResponseWriter, r *http.Request) error
 }
 
+// Guard decides whether a request may reach a route. Guards run after the
+// route is matched and before its handler; a returned error is rendered
+// like a handler error. route is the registered Route or AppRoute, so that
+// guards can act on optional interfaces it implements.
+type Guard interface {
+	Check(w http.ResponseWriter, r *http.Request, route any) error
+}
+
+// AsGuard annotates the given constructor to state that
+// it provides a guard to the "guards" group.
+func AsGuard(f any) any {
+	return fx.Annotate(
+		f,
+		fx.As(new(Guard)),
+		fx.ResultTags(`group:"guards"`),
+	)
+}
+
+func checkGuards(w http.ResponseWriter, r *http.Request, guards []Guard, route any) error {
+	for _, g := range guards {
+		if err := g.Check(w, r, route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // appRouteAdapter wraps an AppRoute to implement Route.
 type appRouteAdapter struct {
 	route  AppRoute
+	guards []Guard
 	logger *slog.Logger
 }
 
@@ -48,32 +75,45 @@ func (a *appRouteAdapter) Pattern() string {
 }
 
 func (a *appRouteAdapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if err := a.route.Handle(w, r); err != nil {
-		handleError(w, a.logger, err)
+	err := checkGuards(w, r, a.guards, a.route)
+	if err == nil {
+		err = a.route.Handle(w, r)
 	}
+	if err != nil {
+		handleError(w, r, a.logger, err)
+	}
+}
+
+// guardedRoute applies the guards to a plain Route.
+type guardedRoute struct {
+	Route
+	guards []Guard
+	logger *slog.Logger
 }
 
-func handleError(w http.ResponseWriter, logger *slog.Logger, err error) {
-	if appErr, ok := err.(*apperrors.AppError); ok {
-		writeJSONError(w, appErr.Code, appErr.Message, appErr.Details)
+func (g *guardedRoute) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := checkGuards(w, r, g.guards, g.Route); err != nil {
+		handleError(w, r, g.logger, err)
 		return
 	}
-	logger.Error("unhandled error", "error", err)
-	writeJSONError(w, http.StatusInternalServerError, "internal server error", nil)
+	g.Route.ServeHTTP(w, r)
 }
 
-type errorResponse struct {
-	Message string `json:"message"`
-	Details any    `json:"details,omitempty"`
+// WriteError renders err the way errors returned by an AppRoute are
+// rendered, for middlewares that reject requests.
+func WriteError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, err error) {
+	handleError(w, r, logger, err)
 }
 
-func writeJSONError(w http.ResponseWriter, code int, message string, details any) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(code)
-	_ = json.MarshalWrite(w, errorResponse{
-		Message: message,
-		Details: details,
-	})
+func handleError(w http.ResponseWriter, r *http.Request, logger *slog.Logger, err error) {
+	// Wrapped AppErrors and classified domain errors are rendered as is;
+	// anything else is an unexpected failure and hidden from the client.
+	if appErr := apperrors.ToAppError(err); appErr != nil {
+		writeJSONError(w, r, appErr)
+		return
+	}
+	logger.ErrorContext(r.Context(), "unhandled error", "error", err, "method", r.Method, "path", r.URL.Path)
+	writeJSONError(w, r, apperrors.NewInternalError("internal server error"))
 }
 
 // AsAppRoute annotates the given constructor to state that
@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"net/http"
 
+	"project_template/pkg/requestid"
+
 	"go.uber.org/fx"
 )
 
@@ -18,28 +20,41 @@ type RouterParams struct {
 	Routes      []Route      `group:"routes"`
 	AppRoutes   []AppRoute   `group:"approutes"`
 	Middlewares []Middleware `group:"middlewares"`
+	Guards      []Guard      `group:"guards"`
+	RateLimiter *RateLimiter
 }
 
 func NewRouter(params RouterParams) *Router {
 	mux := http.NewServeMux()
 	for _, r := range params.Routes {
-		mux.Handle(r.Pattern(), r)
+		var h http.Handler = r
+		if len(params.Guards) > 0 {
+			h = &guardedRoute{Route: r, guards: params.Guards, logger: params.Logger}
+		}
+		mux.Handle(r.Pattern(), recordPattern(params.RateLimiter.wrap(r, r.Pattern(), h)))
 	}
 	for _, r := range params.AppRoutes {
-		adapter := &appRouteAdapter{route: r, logger: params.Logger}
-		mux.Handle(adapter.Pattern(), adapter)
+		adapter := &appRouteAdapter{route: r, guards: params.Guards, logger: params.Logger}
+		mux.Handle(adapter.Pattern(), recordPattern(params.RateLimiter.wrap(r, adapter.Pattern(), adapter)))
 	}
 
+	// Panic recovery is always the innermost wrapper so that outer
+	// middlewares observe the 500 response and the request span.
+	var handler http.Handler = NewRecoveryMiddleware(params.Logger)(mux)
+
 	// Apply middlewares in reverse order so the first middleware in the slice
 	// is the outermost wrapper (executed first on request, last on response)
-	var handler http.Handler = mux
 	for i := len(params.Middlewares) - 1; i >= 0; i-- {
 		handler = params.Middlewares[i](handler)
 	}
 
+	// The request ID is assigned before any other middleware runs so that
+	// every log record and span of the request can carry it.
+	handler = requestid.Middleware(handler)
+
 	return &Router{mux: mux, handler: handler}
 }
 
 func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	r.handler.ServeHTTP(w, req)
+	r.handler.ServeHTTP(w, withRouteInfo(req))
 }
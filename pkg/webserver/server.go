@@ -4,33 +4,104 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"strings"
+
+	"project_template/pkg/shutdown"
 
 	"go.uber.org/fx"
 )
 
-func NewHTTPServer(lc fx.Lifecycle, cfg Config, router *Router, logger *slog.Logger) *http.Server {
+const unixAddressPrefix = "unix:"
+
+func NewHTTPServer(lc fx.Lifecycle, cfg Config, router *Router, logger *slog.Logger, coordinator *shutdown.Coordinator) (*http.Server, error) {
 	srv := &http.Server{
-		Addr:              fmt.Sprintf(":%d", cfg.Port),
+		Addr:              listenAddress(cfg),
 		Handler:           router,
 		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+		MaxHeaderBytes:    cfg.MaxHeaderBytes,
+	}
+
+	if cfg.H2C {
+		var protocols http.Protocols
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+		// Keep HTTP/2 over TLS, which setting Protocols would otherwise drop.
+		protocols.SetHTTP2(true)
+		srv.Protocols = &protocols
+	}
+
+	if cfg.TLS.Enabled {
+		tlsConfig, err := newTLSConfig(cfg.TLS, logger)
+		if err != nil {
+			return nil, err
+		}
+		srv.TLSConfig = tlsConfig
 	}
 
 	lc.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			logger.Info("Starting HTTP server", "port", cfg.Port)
+			// Listen synchronously so that a busy port fails startup.
+			ln, err := listen(srv.Addr)
+			if err != nil {
+				return err
+			}
+			logger.Info("Starting HTTP server", "address", srv.Addr, "tls", cfg.TLS.Enabled, "h2c", cfg.H2C)
 			go func() {
-				if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				var err error
+				if cfg.TLS.Enabled {
+					err = srv.ServeTLS(ln, "", "")
+				} else {
+					err = srv.Serve(ln)
+				}
+				if err != nil && err != http.ErrServerClosed {
 					logger.Error("HTTP server error", "error", err)
 				}
 			}()
 			return nil
 		},
-		OnStop: func(ctx context.Context) error {
-			logger.Info("Stopping HTTP server")
-			return srv.Shutdown(ctx)
-		},
 	})
 
-	return srv
+	coordinator.Register(shutdown.PhaseHTTP, "http", func(ctx context.Context) error {
+		logger.Info("Stopping HTTP server")
+		if err := srv.Shutdown(ctx); err != nil {
+			// Requests still running after the timeout are cut off.
+			_ = srv.Close()
+			return err
+		}
+		return nil
+	})
+
+	return srv, nil
+}
+
+func listenAddress(cfg Config) string {
+	if cfg.Address != "" {
+		return cfg.Address
+	}
+	return fmt.Sprintf(":%d", cfg.Port)
+}
+
+func listen(address string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(address, unixAddressPrefix); ok {
+		// Remove a socket left behind by an unclean exit, but never a
+		// file that is not a socket.
+		if info, err := os.Lstat(path); err == nil {
+			if info.Mode()&os.ModeSocket == 0 {
+				return nil, fmt.Errorf("%s exists and is not a socket", path)
+			}
+			if err := os.Remove(path); err != nil {
+				return nil, fmt.Errorf("removing stale socket: %w", err)
+			}
+		} else if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("checking socket path: %w", err)
+		}
+		return net.Listen("unix", path)
+	}
+	return net.Listen("tcp", address)
 }